@@ -0,0 +1,172 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aescbc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+type aesCBCAlg struct {
+	keySize  int
+	hashFunc func() hash.Hash
+}
+
+func init() {
+	jwa.RegisterContentAlgorithm(jwa.A128CBCHS256, New128)
+	jwa.RegisterContentAlgorithm(jwa.A192CBCHS384, New192)
+	jwa.RegisterContentAlgorithm(jwa.A256CBCHS512, New256)
+}
+
+// New128 returns a new A128CBC-HS256 content encryption algorithm.
+func New128() jwa.ContentAlgorithm {
+	return &aesCBCAlg{32, sha256.New}
+}
+
+// New192 returns a new A192CBC-HS384 content encryption algorithm.
+func New192() jwa.ContentAlgorithm {
+	return &aesCBCAlg{48, sha512.New384}
+}
+
+// New256 returns a new A256CBC-HS512 content encryption algorithm.
+func New256() jwa.ContentAlgorithm {
+	return &aesCBCAlg{64, sha512.New}
+}
+
+// KeySize returns the size of the composite key, which is split evenly between
+// MAC key and encryption key.
+func (m *aesCBCAlg) KeySize() int {
+	return m.keySize
+}
+
+func (m *aesCBCAlg) Encrypt(
+	cek, plaintext, aad []byte,
+) ([]byte, []byte, []byte, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, nil, err
+	}
+
+	ciphertext, tag, err := m.seal(cek, iv, plaintext, aad)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return iv, ciphertext, tag, nil
+}
+
+func (m *aesCBCAlg) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != m.keySize {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: m.keySize * 8,
+			Actual:   len(cek) * 8,
+		}
+	}
+
+	if len(iv) != aes.BlockSize || len(tag) != m.keySize/2 ||
+		len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	macKey, encKey := cek[:m.keySize/2], cek[m.keySize/2:]
+	if !hmac.Equal(tag, m.computeTag(macKey, iv, ciphertext, aad)) {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	return unpad(plaintext)
+}
+
+// seal encrypts plaintext using specified initialization vector and computes
+// the authentication tag.
+func (m *aesCBCAlg) seal(cek, iv, plaintext, aad []byte) ([]byte, []byte, error) {
+	if len(cek) != m.keySize {
+		return nil, nil, jwa.ErrInvalidKeySize{
+			Expected: m.keySize * 8,
+			Actual:   len(cek) * 8,
+		}
+	}
+
+	macKey, encKey := cek[:m.keySize/2], cek[m.keySize/2:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext := pad(plaintext)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return ciphertext, m.computeTag(macKey, iv, ciphertext, aad), nil
+}
+
+// computeTag computes the authentication tag as defined by JWA specification.
+// Ref: https://tools.ietf.org/html/rfc7518#section-5.2.2.1
+func (m *aesCBCAlg) computeTag(macKey, iv, ciphertext, aad []byte) []byte {
+	var al [8]byte
+	binary.BigEndian.PutUint64(al[:], uint64(len(aad))*8)
+
+	h := hmac.New(m.hashFunc, macKey)
+	h.Write(aad)
+	h.Write(iv)
+	h.Write(ciphertext)
+	h.Write(al[:])
+
+	return h.Sum(nil)[:m.keySize/2]
+}
+
+// pad returns a copy of specified data padded using PKCS #7.
+func pad(data []byte) []byte {
+	n := aes.BlockSize - len(data)%aes.BlockSize
+	out := make([]byte, len(data)+n)
+	copy(out, data)
+	for i := len(data); i < len(out); i++ {
+		out[i] = byte(n)
+	}
+
+	return out
+}
+
+// unpad removes PKCS #7 padding from specified data.
+func unpad(data []byte) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) {
+		return nil, jwa.ErrDecryption(0)
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, jwa.ErrDecryption(0)
+		}
+	}
+
+	return data[:len(data)-n], nil
+}
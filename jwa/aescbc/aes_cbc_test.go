@@ -0,0 +1,155 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aescbc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+var sealTests = []struct {
+	alg        func() jwa.ContentAlgorithm
+	key        string
+	iv         string
+	plaintext  string
+	aad        string
+	ciphertext string
+	tag        string
+}{
+	// RFC 7516 Appendix A.2 (values encoded as base64url)
+	{
+		New128,
+		"BNMfxVSd_P4LZJ36P6pqzmt81C1vawnbyLEA8I-cLM8",
+		"AxY8DCtDaGlsbGljb3RoZQ",
+		"Live long and prosper.",
+		"eyJhbGciOiJSU0ExXzUiLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0",
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY",
+		"9hH0vgRfYgPnAHOd8stkvw",
+	},
+	// RFC 7518 Appendix B.3 (values encoded as hexadecimal)
+	{
+		New256,
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+			"202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f",
+		"1af38c2dc2b96ffdd86694092341bc04",
+		"A cipher system must not be required to be secret, and it must be " +
+			"able to fall into the hands of the enemy without inconvenience",
+		"The second principle of Auguste Kerckhoffs",
+		"4affaaadb78c31c5da4b1b590d10ffbd3dd8d5d302423526912da037ecbcc7bd" +
+			"822c301dd67c373bccb584ad3e9279c2e6d12a1374b77f077553df829410446b" +
+			"36ebd97066296ae6427ea75c2e0846a11a09ccf5370dc80bfecbad28c73f09b3" +
+			"a3b75e662a2594410ae496b2e2e6609e31e6e02cc837f053d21f37ff4f51950b" +
+			"be2638d09dd7a4930930806d0703b1f6",
+		"4dd3b4c088a7f45c216839645b2012bf2e6269a8c56a816dbc1b267761955bc5",
+	},
+}
+
+func TestSealVectors(t *testing.T) {
+	for i, tc := range sealTests {
+		decode := hex.DecodeString
+		if i == 0 {
+			decode = base64.RawURLEncoding.DecodeString
+		}
+		key, _ := decode(tc.key)
+		iv, _ := decode(tc.iv)
+		ciphertext, _ := decode(tc.ciphertext)
+		tag, _ := decode(tc.tag)
+
+		m := tc.alg().(*aesCBCAlg)
+		out, outTag, err := m.seal(key, iv, []byte(tc.plaintext), []byte(tc.aad))
+		if err != nil {
+			t.Fatalf("Vector %d: error encrypting: %v", i, err)
+		}
+		if !bytes.Equal(out, ciphertext) {
+			t.Errorf("Vector %d: unexpected ciphertext: %x", i, out)
+		}
+		if !bytes.Equal(outTag, tag) {
+			t.Errorf("Vector %d: unexpected tag: %x", i, outTag)
+		}
+
+		plaintext, err := m.Decrypt(key, iv, ciphertext, tag, []byte(tc.aad))
+		if err != nil {
+			t.Fatalf("Vector %d: error decrypting: %v", i, err)
+		}
+		if string(plaintext) != tc.plaintext {
+			t.Errorf("Vector %d: unexpected plaintext: %q", i, plaintext)
+		}
+	}
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	aad := []byte("header")
+	for _, enc := range []string{
+		jwa.A128CBCHS256, jwa.A192CBCHS384, jwa.A256CBCHS512,
+	} {
+		m, err := jwa.NewContentAlgorithm(enc)
+		if err != nil {
+			t.Fatalf("Error creating algorithm %s: %v", enc, err)
+		}
+
+		cek := make([]byte, m.KeySize())
+		for i := range cek {
+			cek[i] = byte(i)
+		}
+
+		// Exercise full block padding
+		for _, plaintext := range [][]byte{
+			[]byte("foo"), bytes.Repeat([]byte("a"), 32), {},
+		} {
+			iv, ciphertext, tag, err := m.Encrypt(cek, plaintext, aad)
+			if err != nil {
+				t.Fatalf("Error encrypting (%s): %v", enc, err)
+			}
+			if len(tag) != m.KeySize()/2 {
+				t.Errorf("Unexpected tag size (%s): %d", enc, len(tag))
+			}
+
+			out, err := m.Decrypt(cek, iv, ciphertext, tag, aad)
+			if err != nil {
+				t.Fatalf("Error decrypting (%s): %v", enc, err)
+			}
+			if !bytes.Equal(out, plaintext) {
+				t.Errorf("Unexpected plaintext (%s): %q", enc, out)
+			}
+
+			if _, err := m.Decrypt(cek, iv, ciphertext, tag,
+				[]byte("other")); err == nil {
+				t.Errorf("Different AAD should fail (%s)", enc)
+			}
+
+			tag[0] ^= 1
+			if _, err := m.Decrypt(cek, iv, ciphertext, tag, aad); err == nil {
+				t.Errorf("Tampered tag should fail (%s)", enc)
+			}
+			tag[0] ^= 1
+
+			ciphertext[0] ^= 1
+			if _, err := m.Decrypt(cek, iv, ciphertext, tag, aad); err == nil {
+				t.Errorf("Tampered ciphertext should fail (%s)", enc)
+			}
+
+			if _, err := m.Decrypt(cek, iv, ciphertext[:0], tag,
+				aad); err == nil {
+				t.Errorf("Empty ciphertext should fail (%s)", enc)
+			}
+		}
+	}
+}
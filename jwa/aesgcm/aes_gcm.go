@@ -0,0 +1,120 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aesgcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+const (
+	// Size in bytes of initialization vector as defined by JWA specification.
+	ivSize = 12
+
+	// Size in bytes of authentication tag as defined by JWA specification.
+	tagSize = 16
+)
+
+type aesGCMAlg struct {
+	keySize int
+}
+
+func init() {
+	jwa.RegisterContentAlgorithm(jwa.A128GCM, New128)
+	jwa.RegisterContentAlgorithm(jwa.A192GCM, New192)
+	jwa.RegisterContentAlgorithm(jwa.A256GCM, New256)
+}
+
+// New128 returns a new A128GCM content encryption algorithm.
+func New128() jwa.ContentAlgorithm {
+	return &aesGCMAlg{16}
+}
+
+// New192 returns a new A192GCM content encryption algorithm.
+func New192() jwa.ContentAlgorithm {
+	return &aesGCMAlg{24}
+}
+
+// New256 returns a new A256GCM content encryption algorithm.
+func New256() jwa.ContentAlgorithm {
+	return &aesGCMAlg{32}
+}
+
+func (m *aesGCMAlg) KeySize() int {
+	return m.keySize
+}
+
+func (m *aesGCMAlg) Encrypt(
+	cek, plaintext, aad []byte,
+) ([]byte, []byte, []byte, error) {
+	aead, err := m.newAEAD(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv := make([]byte, ivSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, nil, err
+	}
+
+	out := aead.Seal(nil, iv, plaintext, aad)
+	tagIdx := len(out) - tagSize
+
+	return iv, out[:tagIdx], out[tagIdx:], nil
+}
+
+func (m *aesGCMAlg) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := m.newAEAD(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != ivSize || len(tag) != tagSize {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	in := make([]byte, 0, len(ciphertext)+len(tag))
+	in = append(in, ciphertext...)
+	in = append(in, tag...)
+
+	plaintext, err := aead.Open(nil, iv, in, aad)
+	if err != nil {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return plaintext, nil
+}
+
+func (m *aesGCMAlg) newAEAD(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != m.keySize {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: m.keySize * 8,
+			Actual:   len(cek) * 8,
+		}
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
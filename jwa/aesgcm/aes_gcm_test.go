@@ -0,0 +1,112 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aesgcm_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/aesgcm"
+)
+
+// Example from RFC 7516 appendix A.1
+const (
+	rfcCEK        = "saH0gFSP4XM_tAP_a5rU9ooHbltwLiJpL4LLLnrqQPw"
+	rfcAAD        = "eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ"
+	rfcIV         = "48V1_ALb6US04U3b"
+	rfcCiphertext = "5eym8TW_c8SuK0ltJ3rpYIzOeDQz7TALvtu6UG9oMo4vpzs9tX_EFShS8iB7j6jiSdiwkIr3ajwQzaBtQD_A"
+	rfcTag        = "XFBoMYUZodetZdvTiFvSkQ"
+	rfcPlaintext  = "The true sign of intelligence is not knowledge but imagination."
+)
+
+func decodeB64(s string, t *testing.T) []byte {
+	out, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Error decoding test value: %v", err)
+	}
+	return out
+}
+
+func TestAESGCMDecrypt(t *testing.T) {
+	method, err := jwa.NewContentAlgorithm(jwa.A256GCM)
+	if err != nil {
+		t.Fatalf("Error while loading algorithm method: %v", err)
+	}
+
+	cek := decodeB64(rfcCEK, t)
+	iv := decodeB64(rfcIV, t)
+	ciphertext := decodeB64(rfcCiphertext, t)
+	tag := decodeB64(rfcTag, t)
+
+	plaintext, err := method.Decrypt(cek, iv, ciphertext, tag, []byte(rfcAAD))
+	if err != nil {
+		t.Fatalf("Error decrypting content: %v", err)
+	}
+	if string(plaintext) != rfcPlaintext {
+		t.Errorf("Unexpected plaintext: %s", plaintext)
+	}
+
+	tag[0] ^= 1
+	if _, err := method.Decrypt(
+		cek, iv, ciphertext, tag, []byte(rfcAAD)); err == nil {
+		t.Error("Tampered tag passed authentication")
+	}
+}
+
+func TestAESGCMRoundTrip(t *testing.T) {
+	for _, alg := range []string{jwa.A128GCM, jwa.A192GCM, jwa.A256GCM} {
+		method, err := jwa.NewContentAlgorithm(alg)
+		if err != nil {
+			t.Errorf("[%s] Error while loading algorithm method: %v", alg, err)
+			continue
+		}
+
+		cek := bytes.Repeat([]byte{0x42}, method.KeySize())
+		aad := []byte("additional data")
+		iv, ciphertext, tag, err := method.Encrypt(
+			cek, []byte(rfcPlaintext), aad)
+		if err != nil {
+			t.Errorf("[%s] Error encrypting content: %v", alg, err)
+			continue
+		}
+
+		plaintext, err := method.Decrypt(cek, iv, ciphertext, tag, aad)
+		if err != nil {
+			t.Errorf("[%s] Error decrypting content: %v", alg, err)
+			continue
+		}
+		if string(plaintext) != rfcPlaintext {
+			t.Errorf("[%s] Unexpected plaintext: %s", alg, plaintext)
+		}
+
+		if _, err := method.Decrypt(
+			cek, iv, ciphertext, tag, []byte("other data")); err == nil {
+			t.Errorf("[%s] Tampered additional data passed authentication",
+				alg)
+		}
+	}
+}
+
+func TestAESGCMInvalidKeySize(t *testing.T) {
+	method, _ := jwa.NewContentAlgorithm(jwa.A128GCM)
+	_, _, _, err := method.Encrypt(make([]byte, 32), []byte("data"), nil)
+	if _, ok := err.(jwa.ErrInvalidKeySize); !ok {
+		t.Errorf("Unexpected error for invalid key size: %v", err)
+	}
+}
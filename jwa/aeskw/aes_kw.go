@@ -0,0 +1,230 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aeskw
+
+import (
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+// Default initial value as defined by RFC 3394 section 2.2.3.1.
+var defaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+type aesKWAlg struct {
+	keySize int
+}
+
+func init() {
+	jwa.RegisterKeyAlgorithm(jwa.A128KW, New128)
+	jwa.RegisterKeyAlgorithm(jwa.A192KW, New192)
+	jwa.RegisterKeyAlgorithm(jwa.A256KW, New256)
+}
+
+// New128 returns a new A128KW key management algorithm.
+func New128() jwa.KeyAlgorithm {
+	return &aesKWAlg{16}
+}
+
+// New192 returns a new A192KW key management algorithm.
+func New192() jwa.KeyAlgorithm {
+	return &aesKWAlg{24}
+}
+
+// New256 returns a new A256KW key management algorithm.
+func New256() jwa.KeyAlgorithm {
+	return &aesKWAlg{32}
+}
+
+// WrapKey generates a random content encryption key and wraps it using
+// specified key. The key must be a []byte matching the algorithm key size.
+func (m *aesKWAlg) WrapKey(
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, []byte, error) {
+	kek, err := m.keyBytes(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, nil, err
+	}
+
+	encKey, err := Wrap(kek, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encKey, nil
+}
+
+// UnwrapKey unwraps the content encryption key using specified key. The key
+// must be a []byte matching the algorithm key size.
+func (m *aesKWAlg) UnwrapKey(
+	encKey []byte,
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	kek, err := m.keyBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := Unwrap(kek, encKey)
+	if err != nil || len(cek) != cekSize {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return cek, nil
+}
+
+// GenerateKey generates a random key of the algorithm key size. The bit size
+// may be zero to use the algorithm key size.
+func (m *aesKWAlg) GenerateKey(bits int) (interface{}, error) {
+	if bits != 0 && bits != m.keySize*8 {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: m.keySize * 8,
+			Actual:   bits,
+		}
+	}
+
+	buf := make([]byte, m.keySize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return buf, nil
+}
+
+func (m *aesKWAlg) keyBytes(key interface{}) ([]byte, error) {
+	kek, ok := key.([]byte)
+	if !ok {
+		return nil, jwa.ErrInvalidKey{Value: key}
+	}
+	if len(kek) != m.keySize {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: m.keySize * 8,
+			Actual:   len(kek) * 8,
+		}
+	}
+
+	return kek, nil
+}
+
+// Wrap wraps specified content encryption key using the AES Key Wrap algorithm
+// as defined by RFC 3394. The key to be wrapped must be a multiple of 64 bits.
+func Wrap(kek, cek []byte) ([]byte, error) {
+	if len(cek) < 16 || len(cek)%8 != 0 {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: (len(cek)/8 + 1) * 64,
+			Actual:   len(cek) * 8,
+		}
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cek) / 8
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], cek[i*8:])
+	}
+
+	buf := make([]byte, 16)
+	copy(buf, defaultIV)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			copy(buf[8:], r[i])
+			block.Encrypt(buf, buf)
+
+			t := uint64(n*j + i + 1)
+			xorCounter(buf[:8], t)
+			copy(r[i], buf[8:])
+		}
+	}
+
+	out := make([]byte, 0, (n+1)*8)
+	out = append(out, buf[:8]...)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+
+	return out, nil
+}
+
+// Unwrap unwraps specified encrypted key using the AES Key Wrap algorithm as
+// defined by RFC 3394. Returns ErrDecryption when the integrity check fails.
+func Unwrap(kek, encKey []byte) ([]byte, error) {
+	if len(encKey) < 24 || len(encKey)%8 != 0 {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(encKey)/8 - 1
+	r := make([][]byte, n)
+	for i := range r {
+		r[i] = make([]byte, 8)
+		copy(r[i], encKey[(i+1)*8:])
+	}
+
+	buf := make([]byte, 16)
+	copy(buf, encKey[:8])
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			xorCounter(buf[:8], t)
+
+			copy(buf[8:], r[i])
+			block.Decrypt(buf, buf)
+			copy(r[i], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(buf[:8], defaultIV) != 1 {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	out := make([]byte, 0, n*8)
+	for i := range r {
+		out = append(out, r[i]...)
+	}
+
+	return out, nil
+}
+
+func xorCounter(a []byte, t uint64) {
+	var tb [8]byte
+	binary.BigEndian.PutUint64(tb[:], t)
+	for i := range a {
+		a[i] ^= tb[i]
+	}
+}
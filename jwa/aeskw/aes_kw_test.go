@@ -0,0 +1,139 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aeskw_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/aeskw"
+)
+
+// Test vectors from RFC 3394 section 4.
+var wrapTests = []struct {
+	kek    string
+	cek    string
+	result string
+}{
+	{
+		"000102030405060708090A0B0C0D0E0F",
+		"00112233445566778899AABBCCDDEEFF",
+		"1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+	},
+	{
+		"000102030405060708090A0B0C0D0E0F1011121314151617",
+		"00112233445566778899AABBCCDDEEFF",
+		"96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D",
+	},
+	{
+		"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		"00112233445566778899AABBCCDDEEFF",
+		"64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+	},
+	{
+		"000102030405060708090A0B0C0D0E0F1011121314151617",
+		"00112233445566778899AABBCCDDEEFF0001020304050607",
+		"031D33264E15D33268F24EC260743EDCE1C6C7DDEE725A936BA814915C6762D2",
+	},
+	{
+		"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		"00112233445566778899AABBCCDDEEFF0001020304050607",
+		"A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1",
+	},
+	{
+		"000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+		"00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+		"28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+	},
+}
+
+func TestWrapVectors(t *testing.T) {
+	for i, tc := range wrapTests {
+		kek, _ := hex.DecodeString(tc.kek)
+		cek, _ := hex.DecodeString(tc.cek)
+		expected, _ := hex.DecodeString(tc.result)
+
+		out, err := aeskw.Wrap(kek, cek)
+		if err != nil {
+			t.Fatalf("Vector %d: error wrapping key: %v", i, err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Errorf("Vector %d: unexpected wrapped key: %X", i, out)
+		}
+
+		out, err = aeskw.Unwrap(kek, expected)
+		if err != nil {
+			t.Fatalf("Vector %d: error unwrapping key: %v", i, err)
+		}
+		if !bytes.Equal(out, cek) {
+			t.Errorf("Vector %d: unexpected unwrapped key: %X", i, out)
+		}
+
+		expected[0] ^= 1
+		if _, err := aeskw.Unwrap(kek, expected); err == nil {
+			t.Errorf("Vector %d: tampered key should not be unwrapped", i)
+		}
+	}
+}
+
+func TestWrapAndUnwrap(t *testing.T) {
+	algs := map[string]int{
+		jwa.A128KW: 128,
+		jwa.A192KW: 192,
+		jwa.A256KW: 256,
+	}
+
+	for alg, bits := range algs {
+		method, err := jwa.NewKeyAlgorithm(alg)
+		if err != nil {
+			t.Fatalf("Error creating algorithm %s: %v", alg, err)
+		}
+
+		key, err := method.GenerateKey(0)
+		if err != nil {
+			t.Fatalf("Error generating key: %v", err)
+		}
+		if len(key.([]byte))*8 != bits {
+			t.Errorf("Unexpected key size for %s: %d", alg, len(key.([]byte)))
+		}
+
+		cek, encKey, err := method.WrapKey(32, key, nil)
+		if err != nil {
+			t.Fatalf("Error wrapping key: %v", err)
+		}
+
+		out, err := method.UnwrapKey(encKey, 32, key, nil)
+		if err != nil {
+			t.Fatalf("Error unwrapping key: %v", err)
+		}
+		if !bytes.Equal(cek, out) {
+			t.Errorf("Unwrapped key does not match for %s", alg)
+		}
+
+		if _, err := method.UnwrapKey(encKey, 16, key, nil); err == nil {
+			t.Errorf("Unexpected CEK size should fail for %s", alg)
+		}
+		if _, _, err := method.WrapKey(32, make([]byte, 8), nil); err == nil {
+			t.Errorf("Invalid key size should fail for %s", alg)
+		}
+		if _, err := method.GenerateKey(bits + 64); err == nil {
+			t.Errorf("Invalid generated key size should fail for %s", alg)
+		}
+	}
+}
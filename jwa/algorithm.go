@@ -65,6 +65,10 @@ const (
 	// ES384 defines an ECDSA algorithm using P-521 and SHA-512.
 	ES512 = "ES512" // import github.com/raiqub/jose/jwa/ecdsa
 
+	// ES256K defines an ECDSA algorithm using secp256k1 and SHA-256
+	// (RFC 8812).
+	ES256K = "ES256K" // import github.com/raiqub/jose/jwa/ecdsa
+
 	// ES256 defines an RSASSA-PSS algorithm using SHA-256 and MGF1 with
 	// SHA-256.
 	PS256 = "PS256" // import github.com/raiqub/jose/jwa/pss
@@ -76,6 +80,10 @@ const (
 	// ES384 defines an RSASSA-PSS algorithm using SHA-512 and MGF1 with
 	// SHA-512.
 	PS512 = "PS512" // import github.com/raiqub/jose/jwa/pss
+
+	// EdDSA defines an Edwards-curve Digital Signature Algorithm using
+	// Ed25519 (RFC 8037).
+	EdDSA = "EdDSA" // import github.com/raiqub/jose/jwa/eddsa
 )
 
 var algorithms = map[string]func() Algorithm{}
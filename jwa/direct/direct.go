@@ -0,0 +1,106 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package direct
+
+import (
+	"crypto/rand"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+const (
+	// MinimumKeySize defines the minimum key size for shared symmetric keys.
+	MinimumKeySize = 128
+)
+
+type directAlg struct{}
+
+func init() {
+	jwa.RegisterKeyAlgorithm(jwa.Direct, New)
+}
+
+// New returns a new dir key management algorithm.
+func New() jwa.KeyAlgorithm {
+	return &directAlg{}
+}
+
+// WrapKey returns specified key as the content encryption key. The key must be
+// a []byte matching the size required by content encryption algorithm. The
+// encrypted key is always empty.
+func (m *directAlg) WrapKey(
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, []byte, error) {
+	cek, err := keyBytes(key, cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, []byte{}, nil
+}
+
+// UnwrapKey returns specified key as the content encryption key. The key must
+// be a []byte matching the size required by content encryption algorithm and
+// the encrypted key must be empty.
+func (m *directAlg) UnwrapKey(
+	encKey []byte,
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	cek, err := keyBytes(key, cekSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(encKey) != 0 {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return cek, nil
+}
+
+func (m *directAlg) GenerateKey(bits int) (interface{}, error) {
+	if bits < MinimumKeySize {
+		return nil, jwa.ErrTooSmallKeySize{
+			Minimum: MinimumKeySize,
+			Actual:  bits,
+		}
+	}
+
+	buf := make([]byte, bits/8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return buf, nil
+}
+
+func keyBytes(key interface{}, size int) ([]byte, error) {
+	cek, ok := key.([]byte)
+	if !ok {
+		return nil, jwa.ErrInvalidKey{Value: key}
+	}
+	if len(cek) != size {
+		return nil, jwa.ErrInvalidKeySize{
+			Expected: size * 8,
+			Actual:   len(cek) * 8,
+		}
+	}
+
+	return cek, nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package direct_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/direct"
+)
+
+func TestDirectKey(t *testing.T) {
+	method, err := jwa.NewKeyAlgorithm(jwa.Direct)
+	if err != nil {
+		t.Fatalf("Error creating algorithm: %v", err)
+	}
+
+	key, err := method.GenerateKey(256)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	cek, encKey, err := method.WrapKey(32, key, nil)
+	if err != nil {
+		t.Fatalf("Error wrapping key: %v", err)
+	}
+	if len(encKey) != 0 {
+		t.Errorf("Encrypted key should be empty: %X", encKey)
+	}
+	if !bytes.Equal(cek, key.([]byte)) {
+		t.Error("CEK should be the shared key")
+	}
+
+	out, err := method.UnwrapKey(encKey, 32, key, nil)
+	if err != nil {
+		t.Fatalf("Error unwrapping key: %v", err)
+	}
+	if !bytes.Equal(out, cek) {
+		t.Error("Unwrapped key does not match")
+	}
+
+	if _, err := method.UnwrapKey([]byte{1}, 32, key, nil); err == nil {
+		t.Error("Non-empty encrypted key should fail")
+	}
+	if _, _, err := method.WrapKey(16, key, nil); err == nil {
+		t.Error("Key not matching CEK size should fail")
+	}
+	if _, err := method.GenerateKey(64); err == nil {
+		t.Error("Too small key should not be generated")
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ecdhes
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// deriveKey derives a key of the given size in bytes from shared secret z
+// using the Concat KDF as defined by NIST SP 800-56A and specified by JWA.
+// Ref: https://tools.ietf.org/html/rfc7518#section-4.6.2
+func deriveKey(z []byte, alg string, size int, apu, apv []byte) []byte {
+	var otherInfo []byte
+	otherInfo = appendLengthPrefixed(otherInfo, []byte(alg))
+	otherInfo = appendLengthPrefixed(otherInfo, apu)
+	otherInfo = appendLengthPrefixed(otherInfo, apv)
+	otherInfo = appendUint32(otherInfo, uint32(size*8))
+
+	h := sha256.New()
+	out := make([]byte, 0, size+h.Size())
+	for counter := uint32(1); len(out) < size; counter++ {
+		h.Reset()
+		h.Write(appendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		out = h.Sum(out)
+	}
+
+	return out[:size]
+}
+
+func appendLengthPrefixed(dst, data []byte) []byte {
+	dst = appendUint32(dst, uint32(len(data)))
+	return append(dst, data...)
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(dst, buf[:]...)
+}
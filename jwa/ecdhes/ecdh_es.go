@@ -0,0 +1,265 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ecdhes
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/aeskw"
+	jwaecdsa "github.com/raiqub/jose/jwa/ecdsa"
+)
+
+type ecdhESAlg struct {
+	name   string
+	kwSize int
+}
+
+func init() {
+	jwa.RegisterKeyAlgorithm(jwa.ECDHES, New)
+	jwa.RegisterKeyAlgorithm(jwa.ECDHESA128KW, NewA128KW)
+	jwa.RegisterKeyAlgorithm(jwa.ECDHESA192KW, NewA192KW)
+	jwa.RegisterKeyAlgorithm(jwa.ECDHESA256KW, NewA256KW)
+}
+
+// New returns a new ECDH-ES key management algorithm, which uses the agreed
+// key directly as content encryption key.
+func New() jwa.KeyAlgorithm {
+	return &ecdhESAlg{jwa.ECDHES, 0}
+}
+
+// NewA128KW returns a new ECDH-ES+A128KW key management algorithm.
+func NewA128KW() jwa.KeyAlgorithm {
+	return &ecdhESAlg{jwa.ECDHESA128KW, 16}
+}
+
+// NewA192KW returns a new ECDH-ES+A192KW key management algorithm.
+func NewA192KW() jwa.KeyAlgorithm {
+	return &ecdhESAlg{jwa.ECDHESA192KW, 24}
+}
+
+// NewA256KW returns a new ECDH-ES+A256KW key management algorithm.
+func NewA256KW() jwa.KeyAlgorithm {
+	return &ecdhESAlg{jwa.ECDHESA256KW, 32}
+}
+
+// WrapKey generates an ephemeral key pair, agrees upon a key with specified
+// recipient key and determines the content encryption key from it. The key
+// must be either a PEM encoded PKIX ECDSA public key as []byte, an
+// ecdsa.PublicKey structure or an X25519 ecdh.PublicKey structure. The
+// generated ephemeral public key is set to params.
+func (m *ecdhESAlg) WrapKey(
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, []byte, error) {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := jwaecdsa.ParseFromPEM(pem)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key = out
+	}
+
+	z, epk, err := agreeEphemeral(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.EphemeralKey = epk
+
+	if m.kwSize == 0 {
+		cek := deriveKey(z, params.Encryption, cekSize,
+			params.PartyUInfo, params.PartyVInfo)
+		return cek, []byte{}, nil
+	}
+
+	kek := deriveKey(z, m.name, m.kwSize,
+		params.PartyUInfo, params.PartyVInfo)
+
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, nil, err
+	}
+
+	encKey, err := aeskw.Wrap(kek, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encKey, nil
+}
+
+// UnwrapKey agrees upon a key with the ephemeral public key defined by params
+// and determines the content encryption key from it. The key must be either a
+// PEM encoded ECDSA private key as []byte, an ecdsa.PrivateKey structure or an
+// X25519 ecdh.PrivateKey structure.
+func (m *ecdhESAlg) UnwrapKey(
+	encKey []byte,
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := jwaecdsa.ParseFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+
+		key = out
+	}
+
+	z, err := agreeStatic(key, params.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.kwSize == 0 {
+		if len(encKey) != 0 {
+			return nil, jwa.ErrDecryption(0)
+		}
+
+		return deriveKey(z, params.Encryption, cekSize,
+			params.PartyUInfo, params.PartyVInfo), nil
+	}
+
+	kek := deriveKey(z, m.name, m.kwSize,
+		params.PartyUInfo, params.PartyVInfo)
+
+	cek, err := aeskw.Unwrap(kek, encKey)
+	if err != nil || len(cek) != cekSize {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return cek, nil
+}
+
+// GenerateKey generates a key pair whose curve is selected by the given bit
+// size: 256 (default), 384 or 521.
+func (m *ecdhESAlg) GenerateKey(bits int) (interface{}, error) {
+	var curve elliptic.Curve
+	switch bits {
+	case 0, 256:
+		curve = elliptic.P256()
+	case 384:
+		curve = elliptic.P384()
+	case 521:
+		curve = elliptic.P521()
+	default:
+		return nil, jwa.ErrorGeneratingKey(
+			fmt.Sprintf("unsupported curve size: %d", bits))
+	}
+
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return key, nil
+}
+
+// agreeEphemeral generates an ephemeral key pair on the curve of specified
+// recipient public key and computes their shared secret. Returns the shared
+// secret and the ephemeral public key.
+func agreeEphemeral(key interface{}) ([]byte, interface{}, error) {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return agreeEphemeral(&k.PublicKey)
+	case *ecdsa.PublicKey:
+		eph, err := ecdsa.GenerateKey(k.Curve, rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		z, err := sharedSecret(eph, k)
+		if err != nil {
+			return nil, nil, err
+		}
+		return z, &eph.PublicKey, nil
+	case *ecdh.PrivateKey:
+		return agreeEphemeral(k.PublicKey())
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, nil, jwa.ErrInvalidKey{Value: key}
+		}
+
+		eph, err := k.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		z, err := eph.ECDH(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		return z, eph.PublicKey(), nil
+	default:
+		return nil, nil, jwa.ErrInvalidKey{Value: key}
+	}
+}
+
+// agreeStatic computes the shared secret between specified recipient private
+// key and ephemeral public key, which must be on the same curve.
+func agreeStatic(key, epk interface{}) ([]byte, error) {
+	var z []byte
+	var err error
+	switch priv := key.(type) {
+	case *ecdsa.PrivateKey:
+		pub, ok := epk.(*ecdsa.PublicKey)
+		if !ok || pub.Curve != priv.Curve {
+			return nil, jwa.ErrInvalidKey{Value: epk}
+		}
+		z, err = sharedSecret(priv, pub)
+	case *ecdh.PrivateKey:
+		pub, ok := epk.(*ecdh.PublicKey)
+		if !ok || priv.Curve() != ecdh.X25519() ||
+			pub.Curve() != priv.Curve() {
+			return nil, jwa.ErrInvalidKey{Value: epk}
+		}
+		z, err = priv.ECDH(pub)
+	default:
+		return nil, jwa.ErrInvalidKey{Value: key}
+	}
+
+	if err != nil {
+		return nil, jwa.ErrInvalidKey{Value: epk}
+	}
+
+	return z, nil
+}
+
+// sharedSecret computes the ECDH shared secret between specified keys. The
+// public key is validated to be on the curve of private key.
+func sharedSecret(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	ecdhPriv, err := priv.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	ecdhPub, err := pub.ECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	return ecdhPriv.ECDH(ecdhPub)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ecdhes
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+// Keys from RFC 7518 Appendix C.
+var (
+	aliceKey = &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     b64Int("gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0"),
+			Y:     b64Int("SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"),
+		},
+		D: b64Int("0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"),
+	}
+	bobKey = &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     b64Int("weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ"),
+			Y:     b64Int("e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck"),
+		},
+		D: b64Int("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"),
+	}
+)
+
+func TestDeriveKeyVector(t *testing.T) {
+	params := &jwa.KeyParams{
+		Encryption:   jwa.A128GCM,
+		EphemeralKey: &aliceKey.PublicKey,
+		PartyUInfo:   []byte("Alice"),
+		PartyVInfo:   []byte("Bob"),
+	}
+
+	cek, err := New().UnwrapKey([]byte{}, 16, bobKey, params)
+	if err != nil {
+		t.Fatalf("Error deriving key: %v", err)
+	}
+
+	expected := "VqqN6vgjbSBcIijNcacQGg"
+	if result := base64.RawURLEncoding.EncodeToString(cek); result != expected {
+		t.Errorf("Unexpected derived key: %s", result)
+	}
+}
+
+func TestDeriveKeyLong(t *testing.T) {
+	z := []byte("shared secret")
+	long := deriveKey(z, "alg", 48, nil, nil)
+	if len(long) != 48 {
+		t.Fatalf("Unexpected derived key size: %d", len(long))
+	}
+
+	short := deriveKey(z, "alg", 32, nil, nil)
+	if bytes.Equal(long[:32], short) {
+		t.Error("Derived keys of different size should not share prefix")
+	}
+}
+
+func TestWrapAndUnwrap(t *testing.T) {
+	algs := []string{
+		jwa.ECDHES, jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW,
+	}
+
+	for _, alg := range algs {
+		method, _ := jwa.NewKeyAlgorithm(alg)
+		for _, bits := range []int{256, 384, 521} {
+			key, err := method.GenerateKey(bits)
+			if err != nil {
+				t.Fatalf("Error generating key: %v", err)
+			}
+			priv := key.(*ecdsa.PrivateKey)
+
+			params := &jwa.KeyParams{
+				Encryption: jwa.A256GCM,
+				PartyUInfo: []byte("Alice"),
+			}
+			cek, encKey, err := method.WrapKey(32, &priv.PublicKey, params)
+			if err != nil {
+				t.Fatalf("Error wrapping key (%s/%d): %v", alg, bits, err)
+			}
+			if params.EphemeralKey == nil {
+				t.Fatal("Ephemeral key was not defined")
+			}
+			if (alg == jwa.ECDHES) != (len(encKey) == 0) {
+				t.Errorf("Unexpected encrypted key size for %s: %d",
+					alg, len(encKey))
+			}
+
+			out, err := method.UnwrapKey(encKey, 32, priv, params)
+			if err != nil {
+				t.Fatalf("Error unwrapping key (%s/%d): %v", alg, bits, err)
+			}
+			if !bytes.Equal(cek, out) {
+				t.Errorf("Unwrapped key does not match (%s/%d)", alg, bits)
+			}
+
+			params.PartyUInfo = []byte("Mallory")
+			out, err = method.UnwrapKey(encKey, 32, priv, params)
+			if err == nil && bytes.Equal(cek, out) {
+				t.Errorf("Different party info should not match (%s)", alg)
+			}
+		}
+	}
+}
+
+func TestWrapAndUnwrapX25519(t *testing.T) {
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	method := NewA128KW()
+	params := &jwa.KeyParams{Encryption: jwa.A256GCM}
+	cek, encKey, err := method.WrapKey(32, priv.PublicKey(), params)
+	if err != nil {
+		t.Fatalf("Error wrapping key: %v", err)
+	}
+	if _, ok := params.EphemeralKey.(*ecdh.PublicKey); !ok {
+		t.Fatalf("Unexpected ephemeral key: %T", params.EphemeralKey)
+	}
+
+	out, err := method.UnwrapKey(encKey, 32, priv, params)
+	if err != nil {
+		t.Fatalf("Error unwrapping key: %v", err)
+	}
+	if !bytes.Equal(cek, out) {
+		t.Error("Unwrapped key does not match")
+	}
+
+	params.EphemeralKey = &aliceKey.PublicKey
+	if _, err := method.UnwrapKey(encKey, 32, priv, params); err == nil {
+		t.Error("An ECDSA ephemeral key should not be accepted")
+	}
+}
+
+func TestInvalidEphemeralKey(t *testing.T) {
+	invalid := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     b64Int("MTEx"),
+		Y:     b64Int("MTEx"),
+	}
+	p384, _ := New().GenerateKey(384)
+
+	for _, epk := range []interface{}{
+		invalid,
+		&p384.(*ecdsa.PrivateKey).PublicKey,
+		nil,
+	} {
+		params := &jwa.KeyParams{
+			Encryption:   jwa.A128GCM,
+			EphemeralKey: epk,
+		}
+		_, err := New().UnwrapKey([]byte{}, 16, bobKey, params)
+		if _, ok := err.(jwa.ErrInvalidKey); !ok {
+			t.Errorf("Expected invalid key error, got %v", err)
+		}
+	}
+}
+
+func b64Int(s string) *big.Int {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetBytes(b)
+}
@@ -29,29 +29,33 @@ import (
 	"math/big"
 
 	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/secp256k1"
 )
 
-// ErrVerification represents a failure to verify a signature.
-// It is deliberately vague to avoid adaptive attacks.
-var ErrVerification = errors.New("jose/jwa/ecdsa: verification error")
+var (
+	// ErrVerification represents a failure to verify a signature.
+	// It is deliberately vague to avoid adaptive attacks.
+	ErrVerification = errors.New("jose/jwa/ecdsa: verification error")
+)
 
 type ecdsaAlg struct {
-	hashFunc  func() hash.Hash
-	keySize   int
-	curveBits int
+	hashFunc func() hash.Hash
+	keySize  int
+	curve    func() elliptic.Curve
 }
 
 func init() {
 	jwa.RegisterAlgorithm(jwa.ES256, New256)
 	jwa.RegisterAlgorithm(jwa.ES384, New384)
 	jwa.RegisterAlgorithm(jwa.ES512, New512)
+	jwa.RegisterAlgorithm(jwa.ES256K, New256K)
 }
 
 // New256 returns a new ES256 cryptographic algorithm.
 func New256() jwa.Algorithm {
 	return &ecdsaAlg{
 		func() hash.Hash { return sha256.New() },
-		32, 256,
+		32, elliptic.P256,
 	}
 }
 
@@ -59,7 +63,7 @@ func New256() jwa.Algorithm {
 func New384() jwa.Algorithm {
 	return &ecdsaAlg{
 		func() hash.Hash { return sha512.New384() },
-		48, 384,
+		48, elliptic.P384,
 	}
 }
 
@@ -67,7 +71,15 @@ func New384() jwa.Algorithm {
 func New512() jwa.Algorithm {
 	return &ecdsaAlg{
 		func() hash.Hash { return sha512.New() },
-		66, 521,
+		66, elliptic.P521,
+	}
+}
+
+// New256K returns a new ES256K cryptographic algorithm.
+func New256K() jwa.Algorithm {
+	return &ecdsaAlg{
+		func() hash.Hash { return sha256.New() },
+		32, secp256k1.S256,
 	}
 }
 
@@ -97,6 +109,10 @@ func (m *ecdsaAlg) Verify(input, signature string, key interface{}) error {
 		return jwa.ErrInvalidKey{Value: key}
 	}
 
+	if ecdsaKey.Curve != m.curve() {
+		return jwa.ErrInvalidKey{Value: key}
+	}
+
 	// Decode the signature
 	decSig, err := base64.RawURLEncoding.DecodeString(signature)
 	if err != nil {
@@ -145,22 +161,25 @@ func (m *ecdsaAlg) Sign(input string, key interface{}) (string, error) {
 		return "", jwa.ErrInvalidKey{Value: key}
 	}
 
+	if ecdsaKey.Curve != m.curve() {
+		return "", jwa.ErrInvalidKey{Value: key}
+	}
+
 	// Create the hasher
 	hasher := m.hashFunc()
 	hasher.Write([]byte(input))
 
 	// Sign the string and return r, s
-	r, s, err := ecdsa.Sign(rand.Reader, ecdsaKey, hasher.Sum(nil))
+	sign := ecdsa.Sign
+	if ecdsaKey.Curve == secp256k1.S256() {
+		sign = secp256k1.Sign
+	}
+	r, s, err := sign(rand.Reader, ecdsaKey, hasher.Sum(nil))
 	if err != nil {
 		return "", err
 	}
 
 	curveBits := ecdsaKey.Curve.Params().BitSize
-
-	if m.curveBits != curveBits {
-		return "", jwa.ErrInvalidKey{Value: key}
-	}
-
 	keyBytes := curveBits / 8
 	if curveBits%8 > 0 {
 		keyBytes++
@@ -180,20 +199,13 @@ func (m *ecdsaAlg) Sign(input string, key interface{}) (string, error) {
 }
 
 func (m *ecdsaAlg) GenerateKey(int) (interface{}, error) {
-	var curve elliptic.Curve
-	switch m.curveBits {
-	case 256:
-		curve = elliptic.P256()
-	case 384:
-		curve = elliptic.P384()
-	case 521:
-		curve = elliptic.P521()
-	default:
-		return nil, jwa.ErrorGeneratingKey(
-			"Unsupported elliptic curve size")
+	var key *ecdsa.PrivateKey
+	var err error
+	if m.curve() == secp256k1.S256() {
+		key, err = secp256k1.GenerateKey(rand.Reader)
+	} else {
+		key, err = ecdsa.GenerateKey(m.curve(), rand.Reader)
 	}
-
-	key, err := ecdsa.GenerateKey(curve, rand.Reader)
 	if err != nil {
 		return nil, jwa.ErrorGeneratingKey(err.Error())
 	}
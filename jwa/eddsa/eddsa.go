@@ -0,0 +1,121 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+type eddsaAlg struct{}
+
+func init() {
+	jwa.RegisterAlgorithm(jwa.EdDSA, New)
+}
+
+// New returns a new EdDSA cryptographic algorithm.
+func New() jwa.Algorithm {
+	return &eddsaAlg{}
+}
+
+// Implements the Verify method from SigningMethod
+// For this verify method, key must be an ed25519.PublicKey or a PEM encoded
+// PKIX public key as []byte.
+func (m *eddsaAlg) Verify(input, signature string, key interface{}) error {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := ParseFromPEM(pem)
+		if err != nil {
+			return err
+		}
+
+		key = out
+	}
+
+	// Get the key
+	var edKey ed25519.PublicKey
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		edKey = k
+	case ed25519.PrivateKey:
+		edKey = k.Public().(ed25519.PublicKey)
+	default:
+		return jwa.ErrInvalidKey{Value: key}
+	}
+
+	if len(edKey) != ed25519.PublicKeySize {
+		return jwa.ErrInvalidKey{Value: key}
+	}
+
+	// Decode the signature
+	decSig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+
+	// Verify the signature
+	if !ed25519.Verify(edKey, []byte(input), decSig) {
+		return jwa.ErrSignatureInvalid(0)
+	}
+
+	return nil
+}
+
+// Implements the Sign method from SigningMethod
+// For this signing method, key must be an ed25519.PrivateKey or a PEM encoded
+// PKCS8 private key as []byte.
+func (m *eddsaAlg) Sign(input string, key interface{}) (string, error) {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := ParseFromPEM(pem)
+		if err != nil {
+			return "", err
+		}
+
+		key = out
+	}
+
+	// Get the key
+	var edKey ed25519.PrivateKey
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		edKey = k
+	default:
+		return "", jwa.ErrInvalidKey{Value: key}
+	}
+
+	if len(edKey) != ed25519.PrivateKeySize {
+		return "", jwa.ErrInvalidKey{Value: key}
+	}
+
+	sig := ed25519.Sign(edKey, []byte(input))
+	return base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// GenerateKey generates a new Ed25519 private key. The bit size is ignored
+// since Ed25519 keys have a fixed size.
+func (m *eddsaAlg) GenerateKey(int) (interface{}, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return key, nil
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eddsa_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/eddsa"
+	"github.com/raiqub/jose/jwa/jwatest"
+)
+
+const (
+	privKeyFile = "test/ed25519-private.pem"
+	pubKeyFile  = "test/ed25519-public.pem"
+)
+
+var eddsaTestData = []struct {
+	name        string
+	tokenString string
+	valid       bool
+}{
+	{
+		// Example from RFC 8037 appendix A.4
+		"RFC 8037 Ed25519",
+		"eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc.hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+		true,
+	},
+	{
+		"RFC 8037 Ed25519 invalid: tampered payload",
+		"eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmcK.hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+		false,
+	},
+}
+
+func TestEdDSAVerify(t *testing.T) {
+	for i, data := range eddsaTestData {
+		method, err := jwa.New(jwa.EdDSA)
+		if err != nil {
+			t.Errorf("[%s] Error while loading algorithm method: %v",
+				data.name, err)
+			continue
+		}
+
+		filename := pubKeyFile
+		if i%2 == 0 {
+			filename = privKeyFile
+		}
+		key, _ := ioutil.ReadFile(filename)
+		lastDotIdx := strings.LastIndex(data.tokenString, ".")
+		input := data.tokenString[:lastDotIdx]
+		signature := data.tokenString[lastDotIdx+1:]
+
+		err = method.Verify(input, signature, key)
+		if data.valid && err != nil {
+			t.Errorf("[%s] Error while verifying key: %v", data.name, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%s] Invalid key passed validation", data.name)
+		}
+	}
+}
+
+func TestEdDSASign(t *testing.T) {
+	for _, data := range eddsaTestData {
+		if !data.valid {
+			continue
+		}
+
+		method, err := jwa.New(jwa.EdDSA)
+		if err != nil {
+			t.Errorf("[%s] Error while loading algorithm method: %v",
+				data.name, err)
+			continue
+		}
+
+		key, _ := ioutil.ReadFile(privKeyFile)
+		lastDotIdx := strings.LastIndex(data.tokenString, ".")
+		input := data.tokenString[:lastDotIdx]
+		signature := data.tokenString[lastDotIdx+1:]
+
+		// Ed25519 signatures are deterministic
+		sig, err := method.Sign(input, key)
+		if err != nil {
+			t.Errorf("[%s] Error signing token: %v", data.name, err)
+		}
+		if sig != signature {
+			t.Errorf(
+				"[%s] Unexpected signature\nexpected:\n%v\ngot:\n%v",
+				data.name, signature, sig)
+		}
+	}
+}
+
+func TestEdDSAGenerateKey(t *testing.T) {
+	method, _ := jwa.New(jwa.EdDSA)
+	key, err := method.GenerateKey(0)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	sig, err := method.Sign(jwa.EdDSA, key)
+	if err != nil {
+		t.Fatalf("Error signing with generated key: %v", err)
+	}
+	if err := method.Verify(jwa.EdDSA, sig, key); err != nil {
+		t.Errorf("Error verifying with generated key: %v", err)
+	}
+}
+
+func TestEdDSAWrongKeyType(t *testing.T) {
+	method, _ := jwa.New(jwa.EdDSA)
+	if _, err := method.Sign("input", "not a key"); err == nil {
+		t.Error("Signing with invalid key type should fail")
+	}
+}
+
+func BenchmarkEdDSASigning(b *testing.B) {
+	key, _ := ioutil.ReadFile(privKeyFile)
+	parsedKey, err := eddsa.ParseFromPEM(key)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	jwatest.BenchmarkSigning(b, jwa.EdDSA, parsedKey)
+}
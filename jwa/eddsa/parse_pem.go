@@ -0,0 +1,39 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eddsa
+
+import (
+	"github.com/raiqub/jose/jwa"
+)
+
+// ParseFromPEM decodes the first Ed25519 key found on PEM encoded data, which
+// may be a PKCS8 Ed25519 private key, PKIX Ed25519 public key or certificate.
+func ParseFromPEM(key []byte) (interface{}, error) {
+	return ParseFromEncryptedPEM(key, nil)
+}
+
+// ParseFromEncryptedPEM decodes the first Ed25519 key found on PEM encoded data
+// like ParseFromPEM, decrypting encrypted PKCS8 private keys using specified
+// passphrase.
+func ParseFromEncryptedPEM(key, passphrase []byte) (interface{}, error) {
+	bundle, err := jwa.ParsePEM(key, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle.Key(jwa.KeyFamilyOKP)
+}
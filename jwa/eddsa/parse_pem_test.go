@@ -0,0 +1,47 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eddsa_test
+
+import (
+	"crypto/ed25519"
+	"io/ioutil"
+	"testing"
+
+	"github.com/raiqub/jose/jwa/eddsa"
+)
+
+func TestEdDSAKeyParsing(t *testing.T) {
+	key, _ := ioutil.ReadFile(privKeyFile)
+	pubKey, _ := ioutil.ReadFile(pubKeyFile)
+	badKey := []byte("All your base are belong to key")
+
+	if k, e := eddsa.ParseFromPEM(key); e != nil {
+		t.Errorf("Failed to parse valid private key: %v", e)
+	} else if _, ok := k.(ed25519.PrivateKey); !ok {
+		t.Errorf("Unexpected private key type: %T", k)
+	}
+
+	if k, e := eddsa.ParseFromPEM(pubKey); e != nil {
+		t.Errorf("Failed to parse valid public key: %v", e)
+	} else if _, ok := k.(ed25519.PublicKey); !ok {
+		t.Errorf("Unexpected public key type: %T", k)
+	}
+
+	if k, e := eddsa.ParseFromPEM(badKey); e == nil {
+		t.Errorf("Parsed invalid key as valid private key: %v", k)
+	}
+}
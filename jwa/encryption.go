@@ -0,0 +1,211 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwa
+
+// A KeyAlgorithm represents a cryptographic algorithm used to encrypt or to
+// determine the value of the Content Encryption Key (CEK) of a JWE token.
+type KeyAlgorithm interface {
+	// WrapKey determines a CEK of the given size in bytes and returns it
+	// along with its encrypted representation.
+	WrapKey(cekSize int, key interface{}, params *KeyParams) (
+		cek, encKey []byte, err error)
+
+	// UnwrapKey recovers a CEK of the given size in bytes from its encrypted
+	// representation.
+	UnwrapKey(encKey []byte, cekSize int, key interface{},
+		params *KeyParams) ([]byte, error)
+
+	// GenerateKey generates a key pair of the given bit size, or for symmetric
+	// algorithms generates a single key of the given bit size.
+	GenerateKey(bits int) (interface{}, error)
+}
+
+// A ContentAlgorithm represents a cryptographic algorithm used to perform
+// authenticated encryption on the plaintext of a JWE token.
+type ContentAlgorithm interface {
+	// KeySize returns the size in bytes of the CEK required by current
+	// algorithm.
+	KeySize() int
+
+	// Encrypt encrypts and authenticates the plaintext and authenticates the
+	// additional data, returning the generated initialization vector, the
+	// ciphertext and the authentication tag.
+	Encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+
+	// Decrypt decrypts the ciphertext and checks that both ciphertext and
+	// additional data matches the authentication tag.
+	Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+// A KeyParams represents the JOSE header parameters that are shared between a
+// JWE token and its key management algorithm.
+type KeyParams struct {
+	// Encryption defines the content encryption algorithm ("enc").
+	Encryption string
+
+	// EphemeralKey defines the public key created by the originator for use
+	// in key agreement algorithms ("epk").
+	EphemeralKey interface{}
+
+	// PartyUInfo defines information about the producer used by key agreement
+	// algorithms ("apu").
+	PartyUInfo []byte
+
+	// PartyVInfo defines information about the recipient used by key
+	// agreement algorithms ("apv").
+	PartyVInfo []byte
+
+	// Salt defines the salt input used by password-based algorithms ("p2s").
+	Salt []byte
+
+	// Iterations defines the PBKDF2 iteration count used by password-based
+	// algorithms ("p2c").
+	Iterations int
+}
+
+// List of available key management algorithms as defined by JWA specification.
+// Ref: https://tools.ietf.org/html/rfc7518#section-4.1.
+const (
+	// RSAOAEP defines an RSAES OAEP algorithm using default parameters.
+	RSAOAEP = "RSA-OAEP" // import github.com/raiqub/jose/jwa/oaep
+
+	// RSAOAEP256 defines an RSAES OAEP algorithm using SHA-256 and MGF1 with
+	// SHA-256.
+	RSAOAEP256 = "RSA-OAEP-256" // import github.com/raiqub/jose/jwa/oaep
+
+	// A128KW defines an AES Key Wrap algorithm using 128-bit key.
+	A128KW = "A128KW" // import github.com/raiqub/jose/jwa/aeskw
+
+	// A192KW defines an AES Key Wrap algorithm using 192-bit key.
+	A192KW = "A192KW" // import github.com/raiqub/jose/jwa/aeskw
+
+	// A256KW defines an AES Key Wrap algorithm using 256-bit key.
+	A256KW = "A256KW" // import github.com/raiqub/jose/jwa/aeskw
+
+	// ECDHES defines an Elliptic Curve Diffie-Hellman Ephemeral Static key
+	// agreement algorithm using Concat KDF.
+	ECDHES = "ECDH-ES" // import github.com/raiqub/jose/jwa/ecdhes
+
+	// ECDHESA128KW defines an ECDH-ES algorithm using Concat KDF and CEK
+	// wrapped with A128KW.
+	ECDHESA128KW = "ECDH-ES+A128KW" // import github.com/raiqub/jose/jwa/ecdhes
+
+	// ECDHESA192KW defines an ECDH-ES algorithm using Concat KDF and CEK
+	// wrapped with A192KW.
+	ECDHESA192KW = "ECDH-ES+A192KW" // import github.com/raiqub/jose/jwa/ecdhes
+
+	// ECDHESA256KW defines an ECDH-ES algorithm using Concat KDF and CEK
+	// wrapped with A256KW.
+	ECDHESA256KW = "ECDH-ES+A256KW" // import github.com/raiqub/jose/jwa/ecdhes
+
+	// PBES2HS256A128KW defines a PBES2 algorithm using HMAC SHA-256 and CEK
+	// wrapped with A128KW.
+	PBES2HS256A128KW = "PBES2-HS256+A128KW" // import github.com/raiqub/jose/jwa/pbes2
+
+	// PBES2HS384A192KW defines a PBES2 algorithm using HMAC SHA-384 and CEK
+	// wrapped with A192KW.
+	PBES2HS384A192KW = "PBES2-HS384+A192KW" // import github.com/raiqub/jose/jwa/pbes2
+
+	// PBES2HS512A256KW defines a PBES2 algorithm using HMAC SHA-512 and CEK
+	// wrapped with A256KW.
+	PBES2HS512A256KW = "PBES2-HS512+A256KW" // import github.com/raiqub/jose/jwa/pbes2
+
+	// Direct defines the direct use of a shared symmetric key as the content
+	// encryption key.
+	Direct = "dir" // import github.com/raiqub/jose/jwa/direct
+)
+
+// List of available content encryption algorithms as defined by JWA
+// specification.
+// Ref: https://tools.ietf.org/html/rfc7518#section-5.1.
+const (
+	// A128CBCHS256 defines an AES CBC algorithm using 128-bit key and
+	// HMAC SHA-256 authentication.
+	A128CBCHS256 = "A128CBC-HS256" // import github.com/raiqub/jose/jwa/aescbc
+
+	// A192CBCHS384 defines an AES CBC algorithm using 192-bit key and
+	// HMAC SHA-384 authentication.
+	A192CBCHS384 = "A192CBC-HS384" // import github.com/raiqub/jose/jwa/aescbc
+
+	// A256CBCHS512 defines an AES CBC algorithm using 256-bit key and
+	// HMAC SHA-512 authentication.
+	A256CBCHS512 = "A256CBC-HS512" // import github.com/raiqub/jose/jwa/aescbc
+
+	// A128GCM defines an AES GCM algorithm using 128-bit key.
+	A128GCM = "A128GCM" // import github.com/raiqub/jose/jwa/aesgcm
+
+	// A192GCM defines an AES GCM algorithm using 192-bit key.
+	A192GCM = "A192GCM" // import github.com/raiqub/jose/jwa/aesgcm
+
+	// A256GCM defines an AES GCM algorithm using 256-bit key.
+	A256GCM = "A256GCM" // import github.com/raiqub/jose/jwa/aesgcm
+)
+
+var (
+	keyAlgorithms     = map[string]func() KeyAlgorithm{}
+	contentAlgorithms = map[string]func() ContentAlgorithm{}
+)
+
+// NewKeyAlgorithm returns a new KeyAlgorithm for encrypting or determining
+// content encryption keys. Returns ErrAlgUnavailable when the algorithm is not
+// implemented.
+func NewKeyAlgorithm(alg string) (KeyAlgorithm, error) {
+	if m, ok := keyAlgorithms[alg]; ok {
+		return m(), nil
+	}
+
+	return nil, ErrAlgUnavailable(alg)
+}
+
+// KeyAlgorithmAvailable reports whether an implementation of specified key
+// management algorithm code is available.
+func KeyAlgorithmAvailable(alg string) bool {
+	_, ok := keyAlgorithms[alg]
+	return ok
+}
+
+// RegisterKeyAlgorithm registers a function that returns a new instance of the
+// given key management algorithm. This is intended to be called from the init
+// function in packages that implement algorithm methods.
+func RegisterKeyAlgorithm(alg string, f func() KeyAlgorithm) {
+	keyAlgorithms[alg] = f
+}
+
+// NewContentAlgorithm returns a new ContentAlgorithm for encrypting or
+// decrypting content. Returns ErrAlgUnavailable when the algorithm is not
+// implemented.
+func NewContentAlgorithm(enc string) (ContentAlgorithm, error) {
+	if m, ok := contentAlgorithms[enc]; ok {
+		return m(), nil
+	}
+
+	return nil, ErrAlgUnavailable(enc)
+}
+
+// ContentAlgorithmAvailable reports whether an implementation of specified
+// content encryption algorithm code is available.
+func ContentAlgorithmAvailable(enc string) bool {
+	_, ok := contentAlgorithms[enc]
+	return ok
+}
+
+// RegisterContentAlgorithm registers a function that returns a new instance of
+// the given content encryption algorithm. This is intended to be called from
+// the init function in packages that implement algorithm methods.
+func RegisterContentAlgorithm(enc string, f func() ContentAlgorithm) {
+	contentAlgorithms[enc] = f
+}
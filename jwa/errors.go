@@ -30,6 +30,16 @@ func (e ErrAlgUnavailable) Error() string {
 		"The specified algorithm method '%s' is unavailable", string(e))
 }
 
+// An ErrDecryption represents an error when encrypted content could not be
+// decrypted or authenticated. It is deliberately vague to avoid adaptive
+// attacks.
+type ErrDecryption int
+
+// Error returns string representation of current instance error.
+func (e ErrDecryption) Error() string {
+	return "The provided content could not be decrypted"
+}
+
 // An ErrorGeneratingKey represents an error when generating raw key.
 type ErrorGeneratingKey string
 
@@ -48,6 +58,15 @@ func (e ErrHashUnavailable) Error() string {
 		"The specified hash function #%d is unavailable", uint(e))
 }
 
+// An ErrInvalidCertificateChain represents an error when certificates do not
+// form a valid chain.
+type ErrInvalidCertificateChain string
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidCertificateChain) Error() string {
+	return fmt.Sprintf("Invalid certificate chain: %s", string(e))
+}
+
 // An ErrInvalidKey represents an error when cryptografic key type is not
 // supported.
 type ErrInvalidKey struct {
@@ -59,6 +78,34 @@ func (e ErrInvalidKey) Error() string {
 	return fmt.Sprintf("Unsupported key type: %T", e.Value)
 }
 
+// An ErrInvalidIterationCount represents an error when the iteration count of
+// a password-based algorithm is out of allowed range.
+type ErrInvalidIterationCount struct {
+	Minimum int
+	Maximum int
+	Actual  int
+}
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidIterationCount) Error() string {
+	return fmt.Sprintf(
+		"The iteration count must be between %d and %d, got %d",
+		e.Minimum, e.Maximum, e.Actual)
+}
+
+// An ErrInvalidKeySize represents an error when specified key size does not
+// match the size required by algorithm.
+type ErrInvalidKeySize struct {
+	Expected int
+	Actual   int
+}
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidKeySize) Error() string {
+	return fmt.Sprintf("The key size must be %d bits, but got %d",
+		e.Expected, e.Actual)
+}
+
 // An ErrKeyMustBePEMEncoded represents an error when specified key is a
 // byte-array and is not PEM-encoded.
 type ErrKeyMustBePEMEncoded int
@@ -77,6 +124,15 @@ func (e ErrParsingFromPEM) Error() string {
 	return "Error trying to parse key read from PEM-encoded key"
 }
 
+// An ErrPassphrase represents an error when an encrypted PEM block could not be
+// decrypted because the passphrase is either missing or incorrect.
+type ErrPassphrase int
+
+// Error returns string representation of current instance error.
+func (e ErrPassphrase) Error() string {
+	return "The passphrase is missing or incorrect for encrypted PEM block"
+}
+
 // An ErrSignatureInvalid represents an error when token signature doesn't match
 // its content.
 type ErrSignatureInvalid int
@@ -98,3 +154,39 @@ func (e ErrTooSmallKeySize) Error() string {
 	return fmt.Sprintf("The key size must be at least %d bits, but got %d",
 		e.Minimum, e.Actual)
 }
+
+// An ErrTooSmallSaltSize represents an error when the salt input of a
+// password-based algorithm is shorter than required.
+type ErrTooSmallSaltSize struct {
+	Minimum int
+	Actual  int
+}
+
+// Error returns string representation of current instance error.
+func (e ErrTooSmallSaltSize) Error() string {
+	return fmt.Sprintf(
+		"The salt size must be at least %d bytes, but got %d",
+		e.Minimum, e.Actual)
+}
+
+// An ErrUnexpectedKeyType represents an error when PEM-encoded data does not
+// provide a key of the expected family.
+type ErrUnexpectedKeyType struct {
+	Expected string
+	Found    string
+}
+
+// Error returns string representation of current instance error.
+func (e ErrUnexpectedKeyType) Error() string {
+	return fmt.Sprintf("Expected %s key but found %s key",
+		e.Expected, e.Found)
+}
+
+// An ErrUnsupportedPEMEncryption represents an error when an encrypted PEM
+// block uses an unsupported encryption scheme.
+type ErrUnsupportedPEMEncryption string
+
+// Error returns string representation of current instance error.
+func (e ErrUnsupportedPEMEncryption) Error() string {
+	return fmt.Sprintf("Unsupported PEM encryption scheme: %s", string(e))
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oaep
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	"github.com/raiqub/jose/jwa"
+	jwarsa "github.com/raiqub/jose/jwa/rsa"
+)
+
+const (
+	// MinimumRSAKeySize defines the minimum key size for RSA keys as
+	// recommended by security experts.
+	MinimumRSAKeySize = 2048
+)
+
+type rsaOAEPAlg struct {
+	hashFunc func() hash.Hash
+}
+
+func init() {
+	jwa.RegisterKeyAlgorithm(jwa.RSAOAEP, New)
+	jwa.RegisterKeyAlgorithm(jwa.RSAOAEP256, New256)
+}
+
+// New returns a new RSA-OAEP key management algorithm.
+func New() jwa.KeyAlgorithm {
+	return &rsaOAEPAlg{func() hash.Hash { return sha1.New() }}
+}
+
+// New256 returns a new RSA-OAEP-256 key management algorithm.
+func New256() jwa.KeyAlgorithm {
+	return &rsaOAEPAlg{func() hash.Hash { return sha256.New() }}
+}
+
+// WrapKey generates a random content encryption key and encrypts it using
+// specified key. The key must be either a PEM encoded PKCS1 or PKCS8 RSA public
+// key as []byte, or an rsa.PublicKey structure.
+func (m *rsaOAEPAlg) WrapKey(
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, []byte, error) {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := jwarsa.ParseFromPEM(pem)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key = out
+	}
+
+	var rsaKey *rsa.PublicKey
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		rsaKey = k
+	case *rsa.PrivateKey:
+		rsaKey = &k.PublicKey
+	default:
+		return nil, nil, jwa.ErrInvalidKey{Value: key}
+	}
+
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, nil, err
+	}
+
+	encKey, err := rsa.EncryptOAEP(m.hashFunc(), rand.Reader, rsaKey, cek, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encKey, nil
+}
+
+// UnwrapKey decrypts the content encryption key using specified key. The key
+// must be either a PEM encoded PKCS1 or PKCS8 RSA private key as []byte, or an
+// rsa.PrivateKey structure.
+func (m *rsaOAEPAlg) UnwrapKey(
+	encKey []byte,
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	// Decode PEM-encoded key
+	if pem, ok := key.([]byte); ok {
+		out, err := jwarsa.ParseFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+
+		key = out
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, jwa.ErrInvalidKey{Value: key}
+	}
+
+	cek, err := rsa.DecryptOAEP(m.hashFunc(), rand.Reader, rsaKey, encKey, nil)
+	if err != nil || len(cek) != cekSize {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return cek, nil
+}
+
+func (m *rsaOAEPAlg) GenerateKey(bits int) (interface{}, error) {
+	if bits < MinimumRSAKeySize {
+		return nil, jwa.ErrTooSmallKeySize{
+			Minimum: MinimumRSAKeySize,
+			Actual:  bits,
+		}
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return key, nil
+}
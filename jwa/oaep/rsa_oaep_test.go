@@ -0,0 +1,91 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oaep_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/oaep"
+	jwarsa "github.com/raiqub/jose/jwa/rsa"
+)
+
+const (
+	privKeyFile = "../rsa/test/sample_key"
+	pubKeyFile  = "../rsa/test/sample_key.pub"
+	cekSize     = 32
+)
+
+func TestRSAOAEPWrapAndUnwrap(t *testing.T) {
+	privKey, _ := ioutil.ReadFile(privKeyFile)
+	pubKey, _ := ioutil.ReadFile(pubKeyFile)
+
+	for _, alg := range []string{jwa.RSAOAEP, jwa.RSAOAEP256} {
+		method, err := jwa.NewKeyAlgorithm(alg)
+		if err != nil {
+			t.Errorf("[%s] Error while loading algorithm method: %v", alg, err)
+			continue
+		}
+
+		cek, encKey, err := method.WrapKey(cekSize, pubKey, &jwa.KeyParams{})
+		if err != nil {
+			t.Errorf("[%s] Error wrapping key: %v", alg, err)
+			continue
+		}
+		if len(cek) != cekSize {
+			t.Errorf("[%s] Unexpected CEK size: %d", alg, len(cek))
+		}
+
+		out, err := method.UnwrapKey(encKey, cekSize, privKey, &jwa.KeyParams{})
+		if err != nil {
+			t.Errorf("[%s] Error unwrapping key: %v", alg, err)
+			continue
+		}
+		if !bytes.Equal(cek, out) {
+			t.Errorf("[%s] Unwrapped key doesn't match", alg)
+		}
+
+		if _, err := method.UnwrapKey(
+			encKey, cekSize+1, privKey, &jwa.KeyParams{}); err == nil {
+			t.Errorf("[%s] Unexpected CEK size passed validation", alg)
+		}
+	}
+}
+
+func TestRSAOAEPUnwrapWithPublicKey(t *testing.T) {
+	pubKey, _ := ioutil.ReadFile(pubKeyFile)
+	parsedKey, err := jwarsa.ParseFromPEM(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method, _ := jwa.NewKeyAlgorithm(jwa.RSAOAEP)
+	_, err = method.UnwrapKey(
+		make([]byte, 256), cekSize, parsedKey, &jwa.KeyParams{})
+	if _, ok := err.(jwa.ErrInvalidKey); !ok {
+		t.Errorf("Unexpected error unwrapping using public key: %v", err)
+	}
+}
+
+func TestRSAOAEPGenerateKey(t *testing.T) {
+	method, _ := jwa.NewKeyAlgorithm(jwa.RSAOAEP256)
+	if _, err := method.GenerateKey(1024); err == nil {
+		t.Error("Small RSA key size should be rejected")
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbes2
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"sync"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/aeskw"
+)
+
+const (
+	// DefaultIterations defines the iteration count used when none is
+	// specified.
+	DefaultIterations = 100000
+
+	// MinimumKeySize defines the minimum size for generated passwords.
+	MinimumKeySize = 128
+
+	// MinimumSaltSize defines the minimum size in bytes of salt input as
+	// required by JWA specification.
+	// Ref: https://tools.ietf.org/html/rfc7518#section-4.8.1.1
+	MinimumSaltSize = 8
+
+	// Size in bytes of generated salt input.
+	saltSize = 16
+)
+
+var (
+	limitsMutex   sync.RWMutex
+	minIterations = 1000
+	maxIterations = 1000000
+)
+
+// SetIterationLimits defines the range of iteration counts accepted when
+// encrypting or decrypting keys. The maximum limits the cost of decrypting a
+// token whose header was crafted by an attacker. It is safe to call
+// concurrently with encryption and decryption.
+func SetIterationLimits(min, max int) {
+	limitsMutex.Lock()
+	defer limitsMutex.Unlock()
+
+	minIterations = min
+	maxIterations = max
+}
+
+// iterationLimits returns the range of accepted iteration counts.
+func iterationLimits() (int, int) {
+	limitsMutex.RLock()
+	defer limitsMutex.RUnlock()
+
+	return minIterations, maxIterations
+}
+
+type pbes2Alg struct {
+	name     string
+	hashFunc func() hash.Hash
+	kwSize   int
+}
+
+func init() {
+	jwa.RegisterKeyAlgorithm(jwa.PBES2HS256A128KW, New256)
+	jwa.RegisterKeyAlgorithm(jwa.PBES2HS384A192KW, New384)
+	jwa.RegisterKeyAlgorithm(jwa.PBES2HS512A256KW, New512)
+}
+
+// New256 returns a new PBES2-HS256+A128KW key management algorithm.
+func New256() jwa.KeyAlgorithm {
+	return &pbes2Alg{jwa.PBES2HS256A128KW, sha256.New, 16}
+}
+
+// New384 returns a new PBES2-HS384+A192KW key management algorithm.
+func New384() jwa.KeyAlgorithm {
+	return &pbes2Alg{jwa.PBES2HS384A192KW, sha512.New384, 24}
+}
+
+// New512 returns a new PBES2-HS512+A256KW key management algorithm.
+func New512() jwa.KeyAlgorithm {
+	return &pbes2Alg{jwa.PBES2HS512A256KW, sha512.New, 32}
+}
+
+// WrapKey generates a random content encryption key and wraps it using a key
+// derived from specified password. The password must be either a []byte or a
+// string. A random salt and the default iteration count are set to params when
+// not defined.
+func (m *pbes2Alg) WrapKey(
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, []byte, error) {
+	if len(params.Salt) == 0 {
+		params.Salt = make([]byte, saltSize)
+		if _, err := io.ReadFull(rand.Reader, params.Salt); err != nil {
+			return nil, nil, err
+		}
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultIterations
+		if min, _ := iterationLimits(); params.Iterations < min {
+			params.Iterations = min
+		}
+	}
+
+	kek, err := m.deriveKey(key, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+		return nil, nil, err
+	}
+
+	encKey, err := aeskw.Wrap(kek, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encKey, nil
+}
+
+// UnwrapKey unwraps the content encryption key using a key derived from
+// specified password. The password must be either a []byte or a string.
+func (m *pbes2Alg) UnwrapKey(
+	encKey []byte,
+	cekSize int,
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	kek, err := m.deriveKey(key, params)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := aeskw.Unwrap(kek, encKey)
+	if err != nil || len(cek) != cekSize {
+		return nil, jwa.ErrDecryption(0)
+	}
+
+	return cek, nil
+}
+
+// GenerateKey generates a random password of the given bit size.
+func (m *pbes2Alg) GenerateKey(bits int) (interface{}, error) {
+	if bits < MinimumKeySize {
+		return nil, jwa.ErrTooSmallKeySize{
+			Minimum: MinimumKeySize,
+			Actual:  bits,
+		}
+	}
+
+	buf := make([]byte, bits/8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, jwa.ErrorGeneratingKey(err.Error())
+	}
+
+	return buf, nil
+}
+
+// deriveKey derives the key encryption key from specified password as
+// defined by JWA specification.
+// Ref: https://tools.ietf.org/html/rfc7518#section-4.8.1.1
+func (m *pbes2Alg) deriveKey(
+	key interface{},
+	params *jwa.KeyParams,
+) ([]byte, error) {
+	var password string
+	switch k := key.(type) {
+	case []byte:
+		password = string(k)
+	case string:
+		password = k
+	default:
+		return nil, jwa.ErrInvalidKey{Value: key}
+	}
+
+	if len(params.Salt) < MinimumSaltSize {
+		return nil, jwa.ErrTooSmallSaltSize{
+			Minimum: MinimumSaltSize,
+			Actual:  len(params.Salt),
+		}
+	}
+
+	min, max := iterationLimits()
+	if params.Iterations < min || params.Iterations > max {
+		return nil, jwa.ErrInvalidIterationCount{
+			Minimum: min,
+			Maximum: max,
+			Actual:  params.Iterations,
+		}
+	}
+
+	salt := make([]byte, 0, len(m.name)+1+len(params.Salt))
+	salt = append(salt, m.name...)
+	salt = append(salt, 0)
+	salt = append(salt, params.Salt...)
+
+	return pbkdf2.Key(m.hashFunc, password, salt, params.Iterations, m.kwSize)
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pbes2_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/pbes2"
+)
+
+func TestUnwrapVector(t *testing.T) {
+	// RFC 7517 Appendix C
+	salt, _ := base64.RawURLEncoding.DecodeString("2WCTcJZ1Rvd_CJuJripQ1w")
+	cek, _ := base64.RawURLEncoding.DecodeString(
+		"bxsZNEIdFE5csDjwQdBScKGDJDfK7LmsgReZwsMw_bY")
+	encKey, _ := base64.RawURLEncoding.DecodeString(
+		"TrqXOwuNUfDV9VPTNbyGvEJ9JMjefAVn-TR1uIxR9p6hsRQh9Tk7BA")
+	password := "Thus from my lips, by yours, my sin is purged."
+
+	params := &jwa.KeyParams{
+		Salt:       salt,
+		Iterations: 4096,
+	}
+
+	out, err := pbes2.New256().UnwrapKey(encKey, 32, password, params)
+	if err != nil {
+		t.Fatalf("Error unwrapping key: %v", err)
+	}
+	if !bytes.Equal(out, cek) {
+		t.Errorf("Unexpected unwrapped key: %x", out)
+	}
+
+	_, err = pbes2.New256().UnwrapKey(encKey, 32, "wrong password", params)
+	if _, ok := err.(jwa.ErrDecryption); !ok {
+		t.Errorf("Expected decryption error, got %v", err)
+	}
+}
+
+func TestWrapAndUnwrap(t *testing.T) {
+	for _, alg := range []string{
+		jwa.PBES2HS256A128KW, jwa.PBES2HS384A192KW, jwa.PBES2HS512A256KW,
+	} {
+		method, err := jwa.NewKeyAlgorithm(alg)
+		if err != nil {
+			t.Fatalf("Error creating algorithm %s: %v", alg, err)
+		}
+
+		password, err := method.GenerateKey(256)
+		if err != nil {
+			t.Fatalf("Error generating password: %v", err)
+		}
+
+		params := &jwa.KeyParams{Iterations: 2000}
+		cek, encKey, err := method.WrapKey(32, password, params)
+		if err != nil {
+			t.Fatalf("Error wrapping key (%s): %v", alg, err)
+		}
+		if len(params.Salt) == 0 {
+			t.Error("Salt was not generated")
+		}
+
+		out, err := method.UnwrapKey(encKey, 32, password, params)
+		if err != nil {
+			t.Fatalf("Error unwrapping key (%s): %v", alg, err)
+		}
+		if !bytes.Equal(cek, out) {
+			t.Errorf("Unwrapped key does not match (%s)", alg)
+		}
+	}
+}
+
+func TestSaltSize(t *testing.T) {
+	method := pbes2.New256()
+
+	params := &jwa.KeyParams{Salt: []byte("1234567")}
+	_, _, err := method.WrapKey(32, "foo", params)
+	if _, ok := err.(jwa.ErrTooSmallSaltSize); !ok {
+		t.Errorf("Expected salt size error, got %v", err)
+	}
+
+	params.Salt = []byte("12345678")
+	cek, encKey, err := method.WrapKey(32, "foo", params)
+	if err != nil {
+		t.Fatalf("Error wrapping key: %v", err)
+	}
+
+	params.Salt = params.Salt[:7]
+	_, err = method.UnwrapKey(encKey, len(cek), "foo", params)
+	if _, ok := err.(jwa.ErrTooSmallSaltSize); !ok {
+		t.Errorf("Expected salt size error, got %v", err)
+	}
+}
+
+func TestIterationLimits(t *testing.T) {
+	method := pbes2.New256()
+
+	params := &jwa.KeyParams{}
+	if _, _, err := method.WrapKey(32, "foo", params); err != nil {
+		t.Fatalf("Error wrapping key: %v", err)
+	}
+	if params.Iterations != pbes2.DefaultIterations {
+		t.Errorf("Unexpected default iteration count: %d", params.Iterations)
+	}
+
+	for _, count := range []int{1, 999, 1000001} {
+		params := &jwa.KeyParams{Iterations: count}
+		_, _, err := method.WrapKey(32, "foo", params)
+		if _, ok := err.(jwa.ErrInvalidIterationCount); !ok {
+			t.Errorf("Expected iteration count error for %d, got %v",
+				count, err)
+		}
+	}
+
+	pbes2.SetIterationLimits(10, 100)
+	defer pbes2.SetIterationLimits(1000, 1000000)
+
+	params = &jwa.KeyParams{Iterations: 10}
+	if _, _, err := method.WrapKey(32, "foo", params); err != nil {
+		t.Errorf("Error wrapping key with custom limits: %v", err)
+	}
+	params.Iterations = 101
+	if _, _, err := method.WrapKey(32, "foo", params); err == nil {
+		t.Error("Iteration count above maximum should fail")
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwa
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	// KeyFamilyEC defines the family of elliptic curve keys used by ECDSA.
+	KeyFamilyEC = "EC"
+
+	// KeyFamilyOKP defines the family of Octet Key Pair keys (RFC 8037).
+	KeyFamilyOKP = "OKP"
+
+	// KeyFamilyRSA defines the family of RSA keys.
+	KeyFamilyRSA = "RSA"
+)
+
+// PEM block types recognized by ParsePEM.
+const (
+	pemCertificate         = "CERTIFICATE"
+	pemECPrivateKey        = "EC PRIVATE KEY"
+	pemEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemPrivateKey          = "PRIVATE KEY"
+	pemPublicKey           = "PUBLIC KEY"
+	pemRSAPrivateKey       = "RSA PRIVATE KEY"
+	pemRSAPublicKey        = "RSA PUBLIC KEY"
+)
+
+// Kinds of content of PEM blocks.
+const (
+	pemKindCertificate = iota
+	pemKindPrivateKey
+	pemKindPublicKey
+)
+
+// Parsers of unencrypted PEM blocks by type, in the order they are tried when a
+// block is mislabelled.
+var pemParsers = []struct {
+	blockType string
+	kind      int
+	parse     func([]byte) (interface{}, error)
+}{
+	{pemRSAPrivateKey, pemKindPrivateKey,
+		func(der []byte) (interface{}, error) {
+			return x509.ParsePKCS1PrivateKey(der)
+		}},
+	{pemECPrivateKey, pemKindPrivateKey,
+		func(der []byte) (interface{}, error) {
+			return x509.ParseECPrivateKey(der)
+		}},
+	{pemPrivateKey, pemKindPrivateKey, x509.ParsePKCS8PrivateKey},
+	{pemPublicKey, pemKindPublicKey, x509.ParsePKIXPublicKey},
+	{pemRSAPublicKey, pemKindPublicKey,
+		func(der []byte) (interface{}, error) {
+			return x509.ParsePKCS1PublicKey(der)
+		}},
+	{pemCertificate, pemKindCertificate,
+		func(der []byte) (interface{}, error) {
+			return x509.ParseCertificate(der)
+		}},
+}
+
+// A PEMBundle represents the keys and certificates read from PEM-encoded data,
+// which are kept in the order they were read.
+type PEMBundle struct {
+	PrivateKeys  []interface{}
+	PublicKeys   []interface{}
+	Certificates []*x509.Certificate
+}
+
+// ParsePEM reads every PEM block of specified data. Private keys may be PKCS#1,
+// SEC 1, PKCS#8 or encrypted PKCS#8, which is decrypted using specified
+// passphrase. Public keys may be PKIX or PKCS#1. Each block is parsed according
+// to its type; when it cannot be parsed so, because it is mislabelled or its
+// type is unknown, every other unencrypted encoding is tried. Blocks which
+// cannot be parsed by any encoding, such as EC parameters, are skipped unless
+// their type is one of the types listed above.
+func ParsePEM(data, passphrase []byte) (*PEMBundle, error) {
+	bundle := &PEMBundle{}
+	found := false
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		found = true
+
+		if err := bundle.add(block, passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	if !found {
+		return nil, ErrKeyMustBePEMEncoded(0)
+	}
+	if len(bundle.PrivateKeys) == 0 && len(bundle.PublicKeys) == 0 &&
+		len(bundle.Certificates) == 0 {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	return bundle, nil
+}
+
+// add parses specified PEM block and appends its content to current bundle.
+func (b *PEMBundle) add(block *pem.Block, passphrase []byte) error {
+	if block.Type == pemEncryptedPrivateKey {
+		key, err := decryptPKCS8(block.Bytes, passphrase)
+		if err != nil {
+			return err
+		}
+
+		b.PrivateKeys = append(b.PrivateKeys, key)
+		return nil
+	}
+
+	known := false
+	for _, p := range pemParsers {
+		if p.blockType == block.Type {
+			known = true
+			if b.parse(p.kind, p.parse, block.Bytes) {
+				return nil
+			}
+		}
+	}
+
+	// Mislabelled blocks are parsed by content
+	for _, p := range pemParsers {
+		if p.blockType != block.Type &&
+			b.parse(p.kind, p.parse, block.Bytes) {
+			return nil
+		}
+	}
+
+	if known {
+		return ErrParsingFromPEM(0)
+	}
+	return nil
+}
+
+// parse parses specified DER-encoded content using specified parser and
+// appends the result to current bundle. Returns whether it was parsed.
+func (b *PEMBundle) parse(
+	kind int,
+	parse func([]byte) (interface{}, error),
+	der []byte,
+) bool {
+	value, err := parse(der)
+	if err != nil {
+		return false
+	}
+
+	switch kind {
+	case pemKindCertificate:
+		b.Certificates = append(b.Certificates, value.(*x509.Certificate))
+	case pemKindPrivateKey:
+		b.PrivateKeys = append(b.PrivateKeys, value)
+	case pemKindPublicKey:
+		b.PublicKeys = append(b.PublicKeys, value)
+	}
+
+	return true
+}
+
+// Key returns the first key of current bundle from specified family, looking
+// for private keys, then public keys and then certificate keys. Any family is
+// accepted when family is empty.
+func (b *PEMBundle) Key(family string) (interface{}, error) {
+	keys := make([]interface{}, 0,
+		len(b.PrivateKeys)+len(b.PublicKeys)+len(b.Certificates))
+	keys = append(keys, b.PrivateKeys...)
+	keys = append(keys, b.PublicKeys...)
+	for _, cert := range b.Certificates {
+		keys = append(keys, cert.PublicKey)
+	}
+
+	for _, key := range keys {
+		if len(family) == 0 || KeyFamily(key) == family {
+			return key, nil
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrParsingFromPEM(0)
+	}
+	return nil, ErrUnexpectedKeyType{family, KeyFamily(keys[0])}
+}
+
+// Chain returns the certificates of current bundle, which must be ordered from
+// leaf certificate and each one must be signed by the next one.
+func (b *PEMBundle) Chain() ([]*x509.Certificate, error) {
+	if len(b.Certificates) == 0 {
+		return nil, ErrInvalidCertificateChain("no certificate found")
+	}
+
+	for i := 0; i < len(b.Certificates)-1; i++ {
+		err := b.Certificates[i].CheckSignatureFrom(b.Certificates[i+1])
+		if err != nil {
+			return nil, ErrInvalidCertificateChain(fmt.Sprintf(
+				"certificate #%d is not signed by next one: %v", i, err))
+		}
+	}
+
+	return b.Certificates, nil
+}
+
+// KeyFamily returns the family of specified raw key, or the Go type of the key
+// when it is not supported.
+func KeyFamily(key interface{}) string {
+	switch k := key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return KeyFamilyRSA
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		return KeyFamilyEC
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return KeyFamilyOKP
+	case *ecdh.PrivateKey:
+		if k.Curve() == ecdh.X25519() {
+			return KeyFamilyOKP
+		}
+	case *ecdh.PublicKey:
+		if k.Curve() == ecdh.X25519() {
+			return KeyFamilyOKP
+		}
+	}
+
+	return fmt.Sprintf("%T", key)
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwa_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+const (
+	ecBundleFile         = "test/ec-bundle.pem"
+	ecEncryptedFile      = "test/ec-encrypted.pem"
+	ecIterationsFile     = "test/ec-iterations.pem"
+	ed25519EncryptedFile = "test/ed25519-encrypted.pem"
+	passphrase           = "jose"
+)
+
+func TestParsePEMBundle(t *testing.T) {
+	data, _ := ioutil.ReadFile(ecBundleFile)
+
+	bundle, err := jwa.ParsePEM(data, nil)
+	if err != nil {
+		t.Fatalf("Error parsing PEM bundle: %v", err)
+	}
+	if len(bundle.PrivateKeys) != 1 {
+		t.Fatalf("Unexpected private keys count: %d", len(bundle.PrivateKeys))
+	}
+
+	if _, err := bundle.Key(jwa.KeyFamilyEC); err != nil {
+		t.Errorf("Error getting EC key: %v", err)
+	}
+	_, err = bundle.Key(jwa.KeyFamilyRSA)
+	if e, ok := err.(jwa.ErrUnexpectedKeyType); !ok ||
+		e.Found != jwa.KeyFamilyEC {
+		t.Errorf("Unexpected error getting RSA key: %v", err)
+	}
+}
+
+func TestParseEncryptedPEM(t *testing.T) {
+	plain, _ := ioutil.ReadFile(ecBundleFile)
+	expected, err := jwa.ParsePEM(plain, nil)
+	if err != nil {
+		t.Fatalf("Error parsing PEM bundle: %v", err)
+	}
+
+	data, _ := ioutil.ReadFile(ecEncryptedFile)
+	bundle, err := jwa.ParsePEM(data, []byte(passphrase))
+	if err != nil {
+		t.Fatalf("Error parsing encrypted PEM: %v", err)
+	}
+	key, _ := bundle.Key(jwa.KeyFamilyEC)
+	if !key.(*ecdsa.PrivateKey).Equal(expected.PrivateKeys[0]) {
+		t.Error("Decrypted key does not match plain key")
+	}
+
+	data, _ = ioutil.ReadFile(ed25519EncryptedFile)
+	bundle, err = jwa.ParsePEM(data, []byte(passphrase))
+	if err != nil {
+		t.Fatalf("Error parsing encrypted PEM: %v", err)
+	}
+	if _, ok := bundle.PrivateKeys[0].(ed25519.PrivateKey); !ok {
+		t.Errorf("Unexpected decrypted key: %T", bundle.PrivateKeys[0])
+	}
+
+	if _, err := jwa.ParsePEM(data, nil); err != jwa.ErrPassphrase(0) {
+		t.Errorf("Unexpected error without passphrase: %v", err)
+	}
+	_, err = jwa.ParsePEM(data, []byte("wrong"))
+	if err != jwa.ErrPassphrase(0) {
+		t.Errorf("Unexpected error using wrong passphrase: %v", err)
+	}
+}
+
+func TestParsePEMChain(t *testing.T) {
+	rootKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(
+		rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("Error creating root certificate: %v", err)
+	}
+	root, _ := x509.ParseCertificate(rootDER)
+
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(
+		rand.Reader, leafTmpl, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("Error creating leaf certificate: %v", err)
+	}
+
+	leafPEM := pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+	rootPEM := pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})
+
+	bundle, err := jwa.ParsePEM(append(leafPEM, rootPEM...), nil)
+	if err != nil {
+		t.Fatalf("Error parsing certificate chain: %v", err)
+	}
+	chain, err := bundle.Chain()
+	if err != nil || len(chain) != 2 {
+		t.Fatalf("Unexpected certificate chain: %v", err)
+	}
+	key, _ := bundle.Key(jwa.KeyFamilyEC)
+	if !leafKey.PublicKey.Equal(key) {
+		t.Error("The key of leaf certificate should be returned")
+	}
+
+	bundle, _ = jwa.ParsePEM(append(rootPEM, leafPEM...), nil)
+	if _, err := bundle.Chain(); err == nil {
+		t.Error("Certificates out of order should be rejected")
+	}
+}
+
+func TestParsePEMErrors(t *testing.T) {
+	if _, err := jwa.ParsePEM([]byte("not PEM"), nil); err == nil {
+		t.Error("Invalid PEM data should be rejected")
+	}
+
+	data := pem.EncodeToMemory(
+		&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})
+	if _, err := jwa.ParsePEM(data, nil); err == nil {
+		t.Error("Malformed key should be rejected")
+	}
+}
+
+func TestParsePEMMislabelled(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error encoding key: %v", err)
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{
+		6, 8, 42, 134, 72, 206, 61, 3, 1, 7}})
+	data = append(data, pem.EncodeToMemory(
+		&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})...)
+
+	bundle, err := jwa.ParsePEM(data, nil)
+	if err != nil {
+		t.Fatalf("Error parsing mislabelled key: %v", err)
+	}
+	parsed, err := bundle.Key(jwa.KeyFamilyEC)
+	if err != nil {
+		t.Fatalf("Error getting key: %v", err)
+	}
+	if !key.Equal(parsed) {
+		t.Error("Parsed key doesn't match original key")
+	}
+}
+
+func TestParsePEMIterations(t *testing.T) {
+	data, err := ioutil.ReadFile(ecIterationsFile)
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+
+	_, err = jwa.ParsePEM(data, []byte(passphrase))
+	if _, ok := err.(jwa.ErrInvalidIterationCount); !ok {
+		t.Errorf("Expected iteration count error, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwa
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"hash"
+)
+
+const (
+	// Maximum iteration count accepted by PBKDF2, which limits the cost of
+	// decrypting a key crafted by an attacker. It matches the default maximum
+	// of PBES2 key management algorithms.
+	maxPBKDF2Iterations = 1000000
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+
+	// Pseudorandom functions supported by PBKDF2.
+	pbkdf2PRFs = map[string]func() hash.Hash{
+		oidHMACWithSHA1.String():                                 sha1.New,
+		asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}.String():  sha256.New224,
+		asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}.String():  sha256.New,
+		asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}.String(): sha512.New384,
+		asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}.String(): sha512.New,
+	}
+
+	// Block ciphers supported by PBES2 using CBC mode.
+	pbes2Ciphers = map[string]pbes2Cipher{
+		asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}.String(): {
+			aes.NewCipher, 16},
+		asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}.String(): {
+			aes.NewCipher, 24},
+		asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}.String(): {
+			aes.NewCipher, 32},
+		asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}.String(): {
+			des.NewTripleDESCipher, 24},
+	}
+)
+
+// A pbes2Cipher represents a block cipher used by PBES2 encryption scheme.
+type pbes2Cipher struct {
+	newCipher func([]byte) (cipher.Block, error)
+	keySize   int
+}
+
+// ASN.1 structures of encrypted PKCS#8 private keys as defined by RFC 5208 and
+// RFC 8018.
+type (
+	encryptedPrivateKeyInfo struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}
+
+	pbes2Params struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}
+
+	pbkdf2Params struct {
+		Salt           []byte
+		IterationCount int
+		KeyLength      int                      `asn1:"optional"`
+		PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+	}
+)
+
+// decryptPKCS8 decrypts specified encrypted PKCS#8 private key using PBES2
+// encryption scheme and specified passphrase.
+func decryptPKCS8(der, passphrase []byte) (interface{}, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrPassphrase(0)
+	}
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, ErrParsingFromPEM(0)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrUnsupportedPEMEncryption(
+			info.Algorithm.Algorithm.String())
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(
+		info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, ErrParsingFromPEM(0)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, ErrUnsupportedPEMEncryption(
+			params.KeyDerivationFunc.Algorithm.String())
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(
+		params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	if kdfParams.IterationCount < 1 ||
+		kdfParams.IterationCount > maxPBKDF2Iterations {
+		return nil, ErrInvalidIterationCount{
+			Minimum: 1,
+			Maximum: maxPBKDF2Iterations,
+			Actual:  kdfParams.IterationCount,
+		}
+	}
+
+	prfOID := kdfParams.PRF.Algorithm
+	if len(prfOID) == 0 {
+		prfOID = oidHMACWithSHA1
+	}
+	prf, ok := pbkdf2PRFs[prfOID.String()]
+	if !ok {
+		return nil, ErrUnsupportedPEMEncryption(prfOID.String())
+	}
+
+	scheme, ok := pbes2Ciphers[params.EncryptionScheme.Algorithm.String()]
+	if !ok {
+		return nil, ErrUnsupportedPEMEncryption(
+			params.EncryptionScheme.Algorithm.String())
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != scheme.keySize {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(
+		params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	key, err := pbkdf2.Key(prf, string(passphrase), kdfParams.Salt,
+		kdfParams.IterationCount, scheme.keySize)
+	if err != nil {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	block, err := scheme.newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 ||
+		len(data)%block.BlockSize() != 0 {
+		return nil, ErrParsingFromPEM(0)
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	// A wrong passphrase is detected by invalid padding or malformed content
+	padLen := int(plain[len(plain)-1])
+	if padLen == 0 || padLen > block.BlockSize() || !bytes.Equal(
+		plain[len(plain)-padLen:],
+		bytes.Repeat([]byte{byte(padLen)}, padLen)) {
+		return nil, ErrPassphrase(0)
+	}
+
+	privKey, err := x509.ParsePKCS8PrivateKey(plain[:len(plain)-padLen])
+	if err != nil {
+		return nil, ErrPassphrase(0)
+	}
+
+	return privKey, nil
+}
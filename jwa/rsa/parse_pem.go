@@ -18,50 +18,24 @@
 package rsa
 
 import (
-	"crypto/x509"
-	"encoding/pem"
-
 	"github.com/raiqub/jose/jwa"
 )
 
-var (
-	parsers = []func([]byte) (interface{}, error){
-		func(key []byte) (interface{}, error) {
-			return x509.ParsePKCS1PrivateKey(key)
-		},
-		func(key []byte) (interface{}, error) {
-			return x509.ParsePKCS8PrivateKey(key)
-		},
-		func(key []byte) (interface{}, error) {
-			return x509.ParsePKIXPublicKey(key)
-		},
-		func(key []byte) (interface{}, error) {
-			cert, err := x509.ParseCertificate(key)
-			if err != nil {
-				return nil, err
-			}
-
-			return cert.PublicKey, nil
-		},
-	}
-)
-
-// ParseFromPEM decodes PEM encoded PKCS1 or PKCS8.
+// ParseFromPEM decodes the first RSA key found on PEM encoded data, which may
+// be a PKCS1 or PKCS8 RSA private key, PKIX or PKCS1 RSA public key or
+// certificate.
 func ParseFromPEM(key []byte) (interface{}, error) {
-	var err error
-
-	// Parse PEM block
-	var block *pem.Block
-	if block, _ = pem.Decode(key); block == nil {
-		return nil, jwa.ErrKeyMustBePEMEncoded(0)
-	}
+	return ParseFromEncryptedPEM(key, nil)
+}
 
-	var parsedKey interface{}
-	for _, v := range parsers {
-		if parsedKey, err = v(block.Bytes); err == nil {
-			return parsedKey, nil
-		}
+// ParseFromEncryptedPEM decodes the first RSA key found on PEM encoded data
+// like ParseFromPEM, decrypting encrypted PKCS8 private keys using specified
+// passphrase.
+func ParseFromEncryptedPEM(key, passphrase []byte) (interface{}, error) {
+	bundle, err := jwa.ParsePEM(key, passphrase)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, jwa.ErrParsingFromPEM(0)
+	return bundle.Key(jwa.KeyFamilyRSA)
 }
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/raiqub/jose/jwa"
 	"github.com/raiqub/jose/jwa/rsa"
 )
 
@@ -46,3 +47,12 @@ func TestRSAKeyParsing(t *testing.T) {
 		t.Errorf("Parsed invalid key as valid private key: %v", k)
 	}
 }
+
+func TestRSAKeyFamily(t *testing.T) {
+	key, _ := ioutil.ReadFile("../ecdsa/test/ec256-private.pem")
+
+	_, e := rsa.ParseFromPEM(key)
+	if _, ok := e.(jwa.ErrUnexpectedKeyType); !ok {
+		t.Errorf("Unexpected error parsing ECDSA key: %v", e)
+	}
+}
@@ -0,0 +1,21 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package secp256k1 implements the secp256k1 elliptic curve used by ES256K
+// algorithm. The curve arithmetic is not constant-time and must only be used
+// with public values, such as verifying signatures. Operations on private keys
+// are delegated to a constant-time implementation of secp256k1.
+package secp256k1
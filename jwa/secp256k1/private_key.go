@@ -0,0 +1,126 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"io"
+	"math/big"
+
+	"gitlab.com/yawning/secp256k1-voi/secec"
+)
+
+// Size in bytes of curve scalars and coordinates.
+const scalarSize = 32
+
+// Maximum number of random scalars rejected when generating a key.
+const maxResamples = 8
+
+// ErrInvalidKey represents an error when a private key either does not belong
+// to secp256k1 curve or does not match its public key.
+var ErrInvalidKey = errors.New("jose/jwa/secp256k1: invalid private key")
+
+// GenerateKey generates a new secp256k1 key pair using specified source of
+// randomness. The public key is computed in constant-time.
+func GenerateKey(rand io.Reader) (*ecdsa.PrivateKey, error) {
+	var d [scalarSize]byte
+	for i := 0; i < maxResamples; i++ {
+		if _, err := io.ReadFull(rand, d[:]); err != nil {
+			return nil, err
+		}
+
+		// Scalars out of range are rejected to avoid biased keys
+		key, err := secec.NewPrivateKey(d[:])
+		if err != nil {
+			continue
+		}
+
+		pub := key.PublicKey().Bytes()
+		return &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: S256(),
+				X:     new(big.Int).SetBytes(pub[1 : 1+scalarSize]),
+				Y:     new(big.Int).SetBytes(pub[1+scalarSize:]),
+			},
+			D: new(big.Int).SetBytes(d[:]),
+		}, nil
+	}
+
+	return nil, ErrInvalidKey
+}
+
+// Sign signs specified hash using specified private key and returns the
+// signature as a pair of integers, like ecdsa.Sign. The signature is computed
+// in constant-time.
+func Sign(
+	rand io.Reader,
+	priv *ecdsa.PrivateKey,
+	hash []byte,
+) (*big.Int, *big.Int, error) {
+	key, err := newPrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, s, _, err := key.SignRaw(rand, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return new(big.Int).SetBytes(r.Bytes()), new(big.Int).SetBytes(s.Bytes()),
+		nil
+}
+
+// ValidatePrivateKey returns an error when specified private key is out of
+// range or does not match its public key.
+func ValidatePrivateKey(priv *ecdsa.PrivateKey) error {
+	_, err := newPrivateKey(priv)
+	return err
+}
+
+// newPrivateKey converts specified private key to its constant-time
+// representation, checking that it matches its public key.
+func newPrivateKey(priv *ecdsa.PrivateKey) (*secec.PrivateKey, error) {
+	if priv.Curve != S256() ||
+		!isScalar(priv.D) || !isScalar(priv.X) || !isScalar(priv.Y) {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := secec.NewPrivateKey(priv.D.FillBytes(make([]byte, scalarSize)))
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	// Uncompressed encoding of public key point
+	pub := make([]byte, 1+2*scalarSize)
+	pub[0] = 4
+	priv.X.FillBytes(pub[1 : 1+scalarSize])
+	priv.Y.FillBytes(pub[1+scalarSize:])
+	if !bytes.Equal(pub, key.PublicKey().Bytes()) {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// isScalar returns whether specified integer is non-negative and fits in a
+// curve scalar.
+func isScalar(n *big.Int) bool {
+	return n != nil && n.Sign() >= 0 && n.BitLen() <= 8*scalarSize
+}
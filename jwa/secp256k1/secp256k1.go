@@ -0,0 +1,283 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secp256k1
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// secp256k1Curve implements the Koblitz curve secp256k1 (y² = x³ + 7) as
+// defined by SEC 2. The elliptic.CurveParams implementation assumes a = -3,
+// so point arithmetic is overridden using Jacobian coordinates for a = 0.
+//
+// This implementation relies on math/big and is not constant-time, therefore
+// it must only be used with public values such as verifying signatures.
+// Private scalars must never be used with this curve, GenerateKey and Sign
+// must be used instead.
+type secp256k1Curve struct {
+	*elliptic.CurveParams
+}
+
+var (
+	initS256 sync.Once
+	s256     *secp256k1Curve
+)
+
+func initSecp256k1() {
+	params := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+	params.P, _ = new(big.Int).SetString(
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	params.N, _ = new(big.Int).SetString(
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	params.B = big.NewInt(7)
+	params.Gx, _ = new(big.Int).SetString(
+		"79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	params.Gy, _ = new(big.Int).SetString(
+		"483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+
+	s256 = &secp256k1Curve{params}
+}
+
+// S256 returns a Curve which implements secp256k1, used by ES256K algorithm.
+// The returned curve is not constant-time and must only be used with public
+// values.
+//
+// Multiple invocations of this function will return the same value, so it can
+// be used for equality checks and switch statements.
+func S256() elliptic.Curve {
+	initS256.Do(initSecp256k1)
+	return s256
+}
+
+// Params returns the parameters for the curve.
+func (c *secp256k1Curve) Params() *elliptic.CurveParams {
+	return c.CurveParams
+}
+
+// IsOnCurve reports whether the given (x,y) lies on the curve.
+func (c *secp256k1Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 ||
+		y.Sign() < 0 || y.Cmp(c.P) >= 0 {
+		return false
+	}
+
+	// y² = x³ + 7
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, c.P)
+
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, c.B)
+	x3.Mod(x3, c.P)
+
+	return x3.Cmp(y2) == 0
+}
+
+// Add returns the sum of (x1,y1) and (x2,y2).
+func (c *secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	z1 := zForAffine(x1, y1)
+	z2 := zForAffine(x2, y2)
+	return c.affineFromJacobian(c.addJacobian(x1, y1, z1, x2, y2, z2))
+}
+
+// Double returns 2*(x,y).
+func (c *secp256k1Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	z1 := zForAffine(x1, y1)
+	return c.affineFromJacobian(c.doubleJacobian(x1, y1, z1))
+}
+
+// ScalarMult returns k*(Bx,By) where k is a number in big-endian form.
+func (c *secp256k1Curve) ScalarMult(
+	bx, by *big.Int, k []byte,
+) (*big.Int, *big.Int) {
+	bz := zForAffine(bx, by)
+	x, y, z := new(big.Int), new(big.Int), new(big.Int)
+
+	for _, b := range k {
+		for bit := 0; bit < 8; bit++ {
+			x, y, z = c.doubleJacobian(x, y, z)
+			if b&0x80 == 0x80 {
+				x, y, z = c.addJacobian(bx, by, bz, x, y, z)
+			}
+			b <<= 1
+		}
+	}
+
+	return c.affineFromJacobian(x, y, z)
+}
+
+// ScalarBaseMult returns k*G, where G is the base point of the group and k is
+// an integer in big-endian form.
+func (c *secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.Gx, c.Gy, k)
+}
+
+// zForAffine returns a Jacobian Z value for the affine point (x, y). If x and
+// y are zero, it assumes that they represent the point at infinity.
+func zForAffine(x, y *big.Int) *big.Int {
+	z := new(big.Int)
+	if x.Sign() != 0 || y.Sign() != 0 {
+		z.SetInt64(1)
+	}
+	return z
+}
+
+// affineFromJacobian reverses the Jacobian transform.
+func (c *secp256k1Curve) affineFromJacobian(
+	x, y, z *big.Int,
+) (*big.Int, *big.Int) {
+	if z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+
+	zinv := new(big.Int).ModInverse(z, c.P)
+	zinvsq := new(big.Int).Mul(zinv, zinv)
+
+	xOut := new(big.Int).Mul(x, zinvsq)
+	xOut.Mod(xOut, c.P)
+	zinvsq.Mul(zinvsq, zinv)
+	yOut := new(big.Int).Mul(y, zinvsq)
+	yOut.Mod(yOut, c.P)
+	return xOut, yOut
+}
+
+// addJacobian takes two points in Jacobian coordinates, (x1, y1, z1) and
+// (x2, y2, z2) and returns their sum, also in Jacobian form.
+// See https://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#addition-add-2007-bl
+func (c *secp256k1Curve) addJacobian(
+	x1, y1, z1, x2, y2, z2 *big.Int,
+) (*big.Int, *big.Int, *big.Int) {
+	x3, y3, z3 := new(big.Int), new(big.Int), new(big.Int)
+	if z1.Sign() == 0 {
+		x3.Set(x2)
+		y3.Set(y2)
+		z3.Set(z2)
+		return x3, y3, z3
+	}
+	if z2.Sign() == 0 {
+		x3.Set(x1)
+		y3.Set(y1)
+		z3.Set(z1)
+		return x3, y3, z3
+	}
+
+	z1z1 := new(big.Int).Mul(z1, z1)
+	z1z1.Mod(z1z1, c.P)
+	z2z2 := new(big.Int).Mul(z2, z2)
+	z2z2.Mod(z2z2, c.P)
+
+	u1 := new(big.Int).Mul(x1, z2z2)
+	u1.Mod(u1, c.P)
+	u2 := new(big.Int).Mul(x2, z1z1)
+	u2.Mod(u2, c.P)
+	h := new(big.Int).Sub(u2, u1)
+	xEqual := h.Sign() == 0
+	if h.Sign() == -1 {
+		h.Add(h, c.P)
+	}
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	j := new(big.Int).Mul(h, i)
+
+	s1 := new(big.Int).Mul(y1, z2)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, c.P)
+	s2 := new(big.Int).Mul(y2, z1)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, c.P)
+	r := new(big.Int).Sub(s2, s1)
+	if r.Sign() == -1 {
+		r.Add(r, c.P)
+	}
+	yEqual := r.Sign() == 0
+	if xEqual && yEqual {
+		return c.doubleJacobian(x1, y1, z1)
+	}
+	r.Lsh(r, 1)
+	v := new(big.Int).Mul(u1, i)
+
+	x3.Set(r)
+	x3.Mul(x3, x3)
+	x3.Sub(x3, j)
+	x3.Sub(x3, v)
+	x3.Sub(x3, v)
+	x3.Mod(x3, c.P)
+
+	y3.Set(r)
+	v.Sub(v, x3)
+	y3.Mul(y3, v)
+	s1.Mul(s1, j)
+	s1.Lsh(s1, 1)
+	y3.Sub(y3, s1)
+	y3.Mod(y3, c.P)
+
+	z3.Add(z1, z2)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, c.P)
+
+	return x3, y3, z3
+}
+
+// doubleJacobian takes a point in Jacobian coordinates, (x, y, z), and
+// returns its double, also in Jacobian form.
+// See https://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#doubling-dbl-2009-l
+func (c *secp256k1Curve) doubleJacobian(
+	x, y, z *big.Int,
+) (*big.Int, *big.Int, *big.Int) {
+	if z.Sign() == 0 || y.Sign() == 0 {
+		return new(big.Int), new(big.Int), new(big.Int)
+	}
+
+	a := new(big.Int).Mul(x, x)
+	a.Mod(a, c.P)
+	b := new(big.Int).Mul(y, y)
+	b.Mod(b, c.P)
+	cc := new(big.Int).Mul(b, b)
+	cc.Mod(cc, c.P)
+
+	d := new(big.Int).Add(x, b)
+	d.Mul(d, d)
+	d.Sub(d, a)
+	d.Sub(d, cc)
+	d.Lsh(d, 1)
+	d.Mod(d, c.P)
+
+	e := new(big.Int).Lsh(a, 1)
+	e.Add(e, a)
+	f := new(big.Int).Mul(e, e)
+
+	x3 := new(big.Int).Lsh(d, 1)
+	x3.Sub(f, x3)
+	x3.Mod(x3, c.P)
+
+	y3 := new(big.Int).Sub(d, x3)
+	y3.Mul(e, y3)
+	cc.Lsh(cc, 3)
+	y3.Sub(y3, cc)
+	y3.Mod(y3, c.P)
+
+	z3 := new(big.Int).Mul(y, z)
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, c.P)
+
+	return x3, y3, z3
+}
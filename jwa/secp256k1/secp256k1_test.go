@@ -0,0 +1,68 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secp256k1_test
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/raiqub/jose/jwa/secp256k1"
+)
+
+// Key pair produced by OpenSSL
+const (
+	testD = "En_qLPALjkf9KbVXVi5zly62AR_a3coOm5SqzCVQGJg"
+	testX = "bX4iqX5s90OzBnKzSXf1bcYZ8h4OrA1gxputvukq46c"
+	testY = "3z69TWH-xoNwfy4B51-nhu8zAarC4KE7cL2e3EHR3cc"
+)
+
+func b64int(t *testing.T, s string) *big.Int {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return new(big.Int).SetBytes(buf)
+}
+
+func TestSecp256k1Curve(t *testing.T) {
+	d, kx, ky := b64int(t, testD), b64int(t, testX), b64int(t, testY)
+	curve := secp256k1.S256()
+
+	if !curve.IsOnCurve(kx, ky) {
+		t.Error("Public key should be on curve")
+	}
+	if curve.IsOnCurve(kx, new(big.Int).Add(ky, big.NewInt(1))) {
+		t.Error("Invalid point reported as being on curve")
+	}
+
+	x, y := curve.ScalarBaseMult(d.Bytes())
+	if x.Cmp(kx) != 0 || y.Cmp(ky) != 0 {
+		t.Error("Unexpected public key derived from private key")
+	}
+
+	params := curve.Params()
+	x, y = curve.Double(params.Gx, params.Gy)
+	x2, y2 := curve.Add(params.Gx, params.Gy, params.Gx, params.Gy)
+	if x.Cmp(x2) != 0 || y.Cmp(y2) != 0 {
+		t.Error("Doubling and adding the base point should match")
+	}
+	x3, y3 := curve.ScalarBaseMult([]byte{2})
+	if x.Cmp(x3) != 0 || y.Cmp(y3) != 0 {
+		t.Error("Doubling and scalar multiplication should match")
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwk"
+	"github.com/raiqub/jose/jws"
+)
+
+// GetKeyFunc defines a function to retrieve a key for specified token. The
+// key might be a *jwk.Key, which must be permitted to decrypt the token by its
+// "use" and "key_ops" members.
+type GetKeyFunc func(*RegHeader) (interface{}, error)
+
+// An EncryptedToken represents a token encapsulated by JWE.
+type EncryptedToken struct {
+	Header  *RegHeader
+	Payload []byte
+}
+
+// NewEncryptedToken creates a new instance of EncryptedToken using default
+// header and specified payload.
+func NewEncryptedToken(alg, enc string, payload []byte) *EncryptedToken {
+	return &EncryptedToken{
+		NewHeader(alg, enc),
+		payload,
+	}
+}
+
+// Decrypt decodes an existing token and decrypts its payload.
+func Decrypt(token string, getKey GetKeyFunc) (*EncryptedToken, error) {
+	// ===== DECODING =====
+
+	segs := strings.Split(token, ".")
+	if len(segs) != 5 {
+		return nil, ErrInvalidFormat(token)
+	}
+
+	b64in, err := base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, err
+	}
+	header := &RegHeader{}
+	if err := ffjson.Unmarshal(b64in, header); err != nil {
+		return nil, err
+	}
+	if len(header.Compression) > 0 {
+		return nil, ErrUnsupportedHeader("zip")
+	}
+	// No extension header parameter is understood, thus any parameter listed
+	// as critical is rejected (RFC 7516 section 4.1.13).
+	if _, err := jws.CheckCritical(b64in, registeredParams, nil); err != nil {
+		return nil, err
+	}
+
+	var parts [4][]byte
+	for i := range parts {
+		if parts[i], err = base64.RawURLEncoding.DecodeString(
+			segs[i+1]); err != nil {
+			return nil, ErrInvalidFormat(token)
+		}
+	}
+	encKey, iv, ciphertext, tag := parts[0], parts[1], parts[2], parts[3]
+
+	// ===== DECRYPTION =====
+
+	keyAlg, err := jwa.NewKeyAlgorithm(header.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	contentAlg, err := jwa.NewContentAlgorithm(header.GetEncryption())
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	if getKey == nil {
+		return nil, ErrGetKey(token)
+	}
+	if key, err = getKey(header); err != nil {
+		return nil, err
+	}
+	key, err = rawKey(key, header.GetAlgorithm(), (*jwk.Key).CheckDecrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := header.keyParams()
+	if err != nil {
+		return nil, ErrInvalidFormat(token)
+	}
+
+	cekSize := contentAlg.KeySize()
+	cek, err := keyAlg.UnwrapKey(encKey, cekSize, key, params)
+	if err != nil {
+		if _, ok := err.(jwa.ErrDecryption); !ok {
+			return nil, err
+		}
+
+		// Proceed using a random key so that a failure to recover the key
+		// can't be distinguished from a failure to decrypt the content.
+		// Ref: https://tools.ietf.org/html/rfc7516#section-11.5
+		cek = make([]byte, cekSize)
+		if _, err := io.ReadFull(rand.Reader, cek); err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := contentAlg.Decrypt(
+		cek, iv, ciphertext, tag, []byte(segs[0]))
+	if err != nil {
+		return nil, ErrDecryption(token)
+	}
+
+	return &EncryptedToken{header, payload}, nil
+}
+
+// Encrypt encrypts current token payload using specified key and creates its
+// compact string representation. The key might be a *jwk.Key, which must be
+// permitted to encrypt the token by its "use" and "key_ops" members.
+func (t *EncryptedToken) Encrypt(key interface{}) (string, error) {
+	if len(t.Header.Compression) > 0 {
+		return "", ErrUnsupportedHeader("zip")
+	}
+	key, err := rawKey(key, t.Header.GetAlgorithm(), (*jwk.Key).CheckEncrypt)
+	if err != nil {
+		return "", err
+	}
+
+	keyAlg, err := jwa.NewKeyAlgorithm(t.Header.GetAlgorithm())
+	if err != nil {
+		return "", err
+	}
+	contentAlg, err := jwa.NewContentAlgorithm(t.Header.GetEncryption())
+	if err != nil {
+		return "", err
+	}
+
+	// KEY
+	params, err := t.Header.keyParams()
+	if err != nil {
+		return "", err
+	}
+	cek, encKey, err := keyAlg.WrapKey(contentAlg.KeySize(), key, params)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Header.setKeyParams(params); err != nil {
+		return "", err
+	}
+
+	// HEADER
+	jheader, err := ffjson.Marshal(t.Header)
+	if err != nil {
+		return "", err
+	}
+	b64header := base64.RawURLEncoding.EncodeToString(jheader)
+
+	// CONTENT
+	iv, ciphertext, tag, err := contentAlg.Encrypt(
+		cek, t.Payload, []byte(b64header))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(b64header)
+	for _, v := range [][]byte{encKey, iv, ciphertext, tag} {
+		buf.WriteString(".")
+		buf.WriteString(base64.RawURLEncoding.EncodeToString(v))
+	}
+
+	return buf.String(), nil
+}
+
+// rawKey returns the raw key of specified key when it is a *jwk.Key, after
+// checking that it is permitted to perform the operation checked by check using
+// specified key management algorithm. Any other key is returned as is.
+func rawKey(
+	key interface{},
+	alg string,
+	check func(*jwk.Key) error,
+) (interface{}, error) {
+	jwkKey, ok := key.(*jwk.Key)
+	if !ok {
+		return key, nil
+	}
+
+	k := *jwkKey
+	if len(k.Algorithm) == 0 {
+		k.Algorithm = alg
+	} else if k.Algorithm != alg {
+		return nil, ErrUnexpectedAlg(alg)
+	}
+	if err := check(&k); err != nil {
+		return nil, err
+	}
+
+	return k.Key()
+}
@@ -0,0 +1,411 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/aescbc"
+	_ "github.com/raiqub/jose/jwa/aesgcm"
+	_ "github.com/raiqub/jose/jwa/aeskw"
+	_ "github.com/raiqub/jose/jwa/direct"
+	_ "github.com/raiqub/jose/jwa/ecdhes"
+	_ "github.com/raiqub/jose/jwa/oaep"
+	"github.com/raiqub/jose/jwe"
+	"github.com/raiqub/jose/jwk"
+	"github.com/raiqub/jose/jws"
+)
+
+// Key from RFC 7516 Appendix A.1.
+const rfcKey = `{"kty":"RSA",
+      "n":"oahUIoWw0K0usKNuOR6H4wkf4oBUXHTxRvgb48E-BVvxkeDNjbC4he8rUWcJoZmds2h7M70imEVhRU5djINXtqllXI4DFqcI1DgjT9LewND8MW2Krf3Spsk_ZkoFnilakGygTwpZ3uesH-PFABNIUYpOiN15dsQRkgr0vEhxN92i2asbOenSZeyaxziK72UwxrrKoExv6kc5twXTq4h-QChLOln0_mtUZwfsRaMStPs6mS6XrgxnxbWhojf663tuEQueGC-FCMfra36C9knDFGzKsNa7LZK2djYgyD3JR_MB_4NUJW_TqOQtwHYbxevoJArm-L5StowjzGy-_bq6Gw",
+      "e":"AQAB",
+      "d":"kLdtIj6GbDks_ApCSTYQtelcNttlKiOyPzMrXHeI-yk1F7-kpDxY4-WY5NWV5KntaEeXS1j82E375xxhWMHXyvjYecPT9fpwR_M9gV8n9Hrh2anTpTD93Dt62ypW3yDsJzBnTnrYu1iwWRgBKrEYY46qAZIrA2xAwnm2X7uGR1hghkqDp0Vqj3kbSCz1XyfCs6_LehBwtxHIyh8Ripy40p24moOAbgxVw3rxT_vlt3UVe4WO3JkJOzlpUf-KTVI2Ptgm-dARxTEtE-id-4OJr0h-K-VFs3VSndVTIznSxfyrj8ILL6MG_Uv8YAu7VILSB3lOW085-4qE3DzgrTjgyQ",
+      "p":"1r52Xk46c-LsfB5P442p7atdPUrxQSy4mti_tZI3Mgf2EuFVbUoDBvaRQ-SWxkbkmoEzL7JXroSBjSrK3YIQgYdMgyAEPTPjXv_hI2_1eTSPVZfzL0lffNn03IXqWF5MDFuoUYE0hzb2vhrlN_rKrbfDIwUbTrjjgieRbwC6Cl0",
+      "q":"wLb35x7hmQWZsWJmB_vle87ihgZ19S8lBEROLIsZG4ayZVe9Hi9gDVCOBmUDdaDYVTSNx_8Fyw1YYa9XGrGnDew00J28cRUoeBB_jKI1oma0Orv1T9aXIWxKwd4gvxFImOWr3QRL9KEBRzk2RatUBnmDZJTIAfwTs0g68UZHvtc",
+      "dp":"ZK-YwE7diUh0qR1tR7w8WHtolDx3MZ_OTowiFvgfeQ3SiresXjm9gZ5KLhMXvo-uz-KUJWDxS5pFQ_M0evdo1dKiRTjVw_x4NyqyXPM5nULPkcpU827rnpZzAJKpdhWAgqrXGKAECQH0Xt4taznjnd_zVpAmZZq60WPMBMfKcuE",
+      "dq":"Dq0gfgJ1DdFGXiLvQEZnuKEN0UUmsJBxkjydc3j4ZYdBiMRAy86x0vHCjywcMlYYg4yoC4YZa9hNVcsjqA3FeiL19rk8g6Qn29Tt0cj8qqyFpz9vNDBUfCAiJVeESOjJDZPYHdHY8v1b-o-Z2X5tvLx-TCekf7oxyeKDUqKWjis",
+      "qi":"VIMpMYbPf47dT1w_zDUXfPimsSegnMOA1zTaX7aGk_8urY6R8-ZW1FxU7AlWAyLWybqq6t16VFd7hQd0y6flUK4SlOydB61gwanOsXGOAOv82cHq0E3eL4HrtZkUuKvnPrMnsUUFlfUdybVzxyjz9JF_XyaY14ardLSjf4L_FNY"}`
+
+// Symmetric key from RFC 7516 Appendix A.3.
+const rfcSymmetricKey = "GawgguFyGrWKav7AX4VKUg"
+
+// Key from RFC 7518 Appendix C.
+const ecKey = `{"kty":"EC","crv":"P-256",
+      "x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",
+      "y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+      "d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`
+
+// Tokens generated by go-jose using ecKey.
+var decryptECDHTests = []string{
+	"eyJhbGciOiJFQ0RILUVTIiwiZW5jIjoiQTEyOEdDTSIsImVwayI6eyJrdHkiOiJFQyIsImNydiI6IlAtMjU2IiwieCI6IkZGUUoyWTZzTGVTdmptejNudjg1c25SNWx5SnpjdFpiNnlvbWViS2RIVEEiLCJ5IjoiaUxhcFdhQ1pxZWFhRGdENmVRb3ozNlVRZEREZEdrekg1QXljVGJVbTJLcyJ9fQ..zDE2AuI8A6-ByT9i.Bp-mM8rFM2ZQlK1VX4y623WQoj1mfQ.N7CbP5HtVl6HvY_rEldnbg",
+	"eyJhbGciOiJFQ0RILUVTK0EyNTZLVyIsImVuYyI6IkEyNTZHQ00iLCJlcGsiOnsia3R5IjoiRUMiLCJjcnYiOiJQLTI1NiIsIngiOiJxdE4ydnVyQlhpRnhKMUhjYnZSS0dlVVlXX29BNmtES3ZYbVV6R3NUSjNvIiwieSI6ImRvZXRYb0JJbjcydmh4OW1TOVRGazVkeTdtdHhYcm1ZcVMzWXdjYTNtLWcifX0.-HJMgQq1W5r1hgKSJplRTGM08gUpVdPGZgN1aoi4wbWYCBWFkPLcbg.jMl0dBQP5UL_e5T7.MHaFmBZ9_iMair-HvNv6tcpc8kAKtA.ULhOQgift0W8T3alGc3TZA",
+}
+
+var decryptTests = []struct {
+	token   string
+	alg     string
+	enc     string
+	payload string
+}{
+	// RFC 7516 Appendix A.1
+	{
+		"eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ.OKOawDo13gRp2ojaHV7LFpZcgV7T6DVZKTyKOMTYUmKoTCVJRgckCL9kiMT03JGeipsEdY3mx_etLbbWSrFr05kLzcSr4qKAq7YN7e9jwQRb23nfa6c9d-StnImGyFDbSv04uVuxIp5Zms1gNxKKK2Da14B8S4rzVRltdYwam_lDp5XnZAYpQdb76FdIKLaVmqgfwX7XWRxv2322i-vDxRfqNzo_tETKzpVLzfiwQyeyPGLBIO56YJ7eObdv0je81860ppamavo35UgoRdbYaBcoh9QcfylQr66oc6vFWXRcZ_ZT2LawVCWTIy3brGPi6UklfCpIMfIjf7iGdXKHzg.48V1_ALb6US04U3b.5eym8TW_c8SuK0ltJ3rpYIzOeDQz7TALvtu6UG9oMo4vpzs9tX_EFShS8iB7j6jiSdiwkIr3ajwQzaBtQD_A.XFBoMYUZodetZdvTiFvSkQ",
+		jwa.RSAOAEP,
+		jwa.A256GCM,
+		"The true sign of intelligence is not knowledge but imagination.",
+	},
+	// RFC 7516 Appendix A.3
+	{
+		"eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0.6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ.AxY8DCtDaGlsbGljb3RoZQ.KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY.U0m_YmjN04DJvceFICbCVQ",
+		jwa.A128KW,
+		jwa.A128CBCHS256,
+		"Live long and prosper.",
+	},
+	// Generated by go-jose
+	{
+		"eyJhbGciOiJSU0EtT0FFUC0yNTYiLCJlbmMiOiJBMTI4R0NNIn0.YX90H3WuRQ43eXYXmSTctg5CI2l4g3bi0Tj8VVh3aX6ndNLnhJ4IUx3g5P3cXodc7GWu_maJuwLhZrxBChXPMGC08oocPLpxlpQQJIr214Gl4uQuQUUibGzZrFiI92-7ue8XLHi8WRt24E15k70XiAWLSVNQm8YrfI7_RGyhHgyXfkHa0mf1uQ5j6PyeDvlYyRN1FoMIV8W3dLjMEik02W7SHW-Rq4Q_0DgTGWZvtmtaHN22VrvO1XmWYIKpU7y5fTovMIKzSmZxgw_rAIOe_huO-1ov6pIsOBCE-EAtcftrt8GB013hFDmEiPyHjwhSm8SieQqF0UesLObjojgYOw.xTQFNTyflDfX9l9v.EXdjkAkryPAgHBUar8UWhqptB2hYEg.wDjcFGPFQknpt0-el9YLMQ",
+		jwa.RSAOAEP256,
+		jwa.A128GCM,
+		"Live long and prosper.",
+	},
+}
+
+func TestDecrypt(t *testing.T) {
+	key := loadKey(t)
+	symKey, _ := base64.RawURLEncoding.DecodeString(rfcSymmetricKey)
+
+	for _, tc := range decryptTests {
+		token, err := jwe.Decrypt(tc.token,
+			func(h *jwe.RegHeader) (interface{}, error) {
+				if h.GetAlgorithm() == jwa.A128KW {
+					return symKey, nil
+				}
+				return key, nil
+			})
+		if err != nil {
+			t.Fatalf("Error decrypting token: %v", err)
+		}
+
+		if token.Header.GetAlgorithm() != tc.alg ||
+			token.Header.GetEncryption() != tc.enc {
+			t.Errorf("Unexpected header: %#v", token.Header)
+		}
+		if string(token.Payload) != tc.payload {
+			t.Errorf("Unexpected payload: %q", token.Payload)
+		}
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	key := loadKey(t)
+	getKey := func(h *jwe.RegHeader) (interface{}, error) {
+		return key, nil
+	}
+
+	segs := strings.Split(decryptTests[0].token, ".")
+	for i := 1; i < len(segs); i++ {
+		tampered := make([]string, len(segs))
+		copy(tampered, segs)
+		tampered[i] = flipFirst(segs[i])
+
+		_, err := jwe.Decrypt(strings.Join(tampered, "."), getKey)
+		if _, ok := err.(jwe.ErrDecryption); !ok {
+			t.Errorf("Segment %d: expected decryption error, got %v", i, err)
+		}
+	}
+
+	if _, err := jwe.Decrypt(strings.Join(segs[:4], "."), getKey); err == nil {
+		t.Error("Token with missing segment should not be decrypted")
+	}
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	key := loadKey(t)
+	plaintext := "Live long and prosper."
+
+	for _, alg := range []string{jwa.RSAOAEP, jwa.RSAOAEP256} {
+		for _, enc := range []string{jwa.A128GCM, jwa.A192GCM, jwa.A256GCM} {
+			token := jwe.NewEncryptedToken(alg, enc, []byte(plaintext))
+			token.Header.ID = "key1"
+
+			str, err := token.Encrypt(&key.PublicKey)
+			if err != nil {
+				t.Fatalf("Error encrypting token (%s/%s): %v", alg, enc, err)
+			}
+			if len(strings.Split(str, ".")) != 5 {
+				t.Fatalf("Invalid compact serialization: %s", str)
+			}
+
+			result, err := jwe.Decrypt(str,
+				func(h *jwe.RegHeader) (interface{}, error) {
+					if h.GetID() != "key1" {
+						t.Errorf("Unexpected key ID: %s", h.GetID())
+					}
+					return key, nil
+				})
+			if err != nil {
+				t.Fatalf("Error decrypting token (%s/%s): %v", alg, enc, err)
+			}
+			if string(result.Payload) != plaintext {
+				t.Errorf("Unexpected payload: %q", result.Payload)
+			}
+		}
+	}
+}
+
+func TestEncryptSymmetric(t *testing.T) {
+	tests := []struct {
+		alg  string
+		enc  string
+		bits int
+	}{
+		{jwa.A128KW, jwa.A256GCM, 128},
+		{jwa.A192KW, jwa.A128GCM, 192},
+		{jwa.A256KW, jwa.A192GCM, 256},
+		{jwa.Direct, jwa.A128GCM, 128},
+		{jwa.Direct, jwa.A256GCM, 256},
+		{jwa.A128KW, jwa.A128CBCHS256, 128},
+		{jwa.A256KW, jwa.A192CBCHS384, 256},
+		{jwa.Direct, jwa.A256CBCHS512, 512},
+	}
+
+	for _, tc := range tests {
+		jwkKey, err := jwk.GenerateKey(tc.alg, tc.bits, 1)
+		if err != nil {
+			t.Fatalf("Error generating key (%s): %v", tc.alg, err)
+		}
+		key, _ := jwkKey.Key()
+
+		token := jwe.NewEncryptedToken(tc.alg, tc.enc, []byte("foo"))
+		str, err := token.Encrypt(key)
+		if err != nil {
+			t.Fatalf("Error encrypting token (%s/%s): %v", tc.alg, tc.enc, err)
+		}
+
+		getKey := func(h *jwe.RegHeader) (interface{}, error) {
+			return key, nil
+		}
+		result, err := jwe.Decrypt(str, getKey)
+		if err != nil {
+			t.Fatalf("Error decrypting token (%s/%s): %v", tc.alg, tc.enc, err)
+		}
+		if string(result.Payload) != "foo" {
+			t.Errorf("Unexpected payload: %q", result.Payload)
+		}
+
+		wrongKey, _ := jwk.GenerateKey(tc.alg, tc.bits, 1)
+		_, err = jwe.Decrypt(str, func(h *jwe.RegHeader) (interface{}, error) {
+			return wrongKey.Key()
+		})
+		if _, ok := err.(jwe.ErrDecryption); !ok {
+			t.Errorf("Expected decryption error (%s/%s), got %v",
+				tc.alg, tc.enc, err)
+		}
+	}
+}
+
+func TestDecryptECDH(t *testing.T) {
+	var k jwk.Key
+	if err := json.Unmarshal([]byte(ecKey), &k); err != nil {
+		t.Fatalf("Error decoding JWK: %v", err)
+	}
+	key, _ := k.Key()
+
+	for _, str := range decryptECDHTests {
+		token, err := jwe.Decrypt(str,
+			func(h *jwe.RegHeader) (interface{}, error) {
+				return key, nil
+			})
+		if err != nil {
+			t.Fatalf("Error decrypting token: %v", err)
+		}
+		if string(token.Payload) != "Live long and prosper." {
+			t.Errorf("Unexpected payload: %q", token.Payload)
+		}
+	}
+}
+
+func TestEncryptECDH(t *testing.T) {
+	algs := []string{
+		jwa.ECDHES, jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW,
+	}
+
+	for _, alg := range algs {
+		jwkKey, err := jwk.GenerateKey(alg, 384, 1)
+		if err != nil {
+			t.Fatalf("Error generating key (%s): %v", alg, err)
+		}
+		rawKey, _ := jwkKey.Key()
+		key := rawKey.(*ecdsa.PrivateKey)
+
+		token := jwe.NewEncryptedToken(alg, jwa.A256GCM, []byte("foo"))
+		token.Header.PartyUInfo = base64.RawURLEncoding.EncodeToString(
+			[]byte("Alice"))
+		token.Header.PartyVInfo = base64.RawURLEncoding.EncodeToString(
+			[]byte("Bob"))
+		str, err := token.Encrypt(&key.PublicKey)
+		if err != nil {
+			t.Fatalf("Error encrypting token (%s): %v", alg, err)
+		}
+
+		epk := token.Header.EphemeralKey
+		if epk == nil || !epk.IsECDSA() || epk.Curve != "P-384" {
+			t.Fatalf("Unexpected ephemeral key: %#v", epk)
+		}
+		if len(epk.D) > 0 || len(epk.ID) > 0 || len(epk.Algorithm) > 0 {
+			t.Errorf("Ephemeral key should only define public parameters")
+		}
+
+		result, err := jwe.Decrypt(str,
+			func(h *jwe.RegHeader) (interface{}, error) {
+				return key, nil
+			})
+		if err != nil {
+			t.Fatalf("Error decrypting token (%s): %v", alg, err)
+		}
+		if string(result.Payload) != "foo" {
+			t.Errorf("Unexpected payload: %q", result.Payload)
+		}
+		if result.Header.PartyUInfo != token.Header.PartyUInfo {
+			t.Errorf("Unexpected apu: %s", result.Header.PartyUInfo)
+		}
+	}
+}
+
+func TestEncryptWithJWK(t *testing.T) {
+	jwkKey, err := jwk.GenerateKey(jwa.RSAOAEP256, 2048, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	if jwkKey.Usage != "enc" {
+		t.Errorf("Unexpected key usage: %s", jwkKey.Usage)
+	}
+
+	pubKey := *jwkKey
+	pubKey.RemovePrivateFields()
+
+	token := jwe.NewEncryptedToken(jwkKey.Algorithm, jwa.A256GCM, []byte("foo"))
+	str, err := token.Encrypt(&pubKey)
+	if err != nil {
+		t.Fatalf("Error encrypting token: %v", err)
+	}
+
+	result, err := jwe.Decrypt(str, func(h *jwe.RegHeader) (interface{}, error) {
+		return jwkKey, nil
+	})
+	if err != nil {
+		t.Fatalf("Error decrypting token: %v", err)
+	}
+	if string(result.Payload) != "foo" {
+		t.Errorf("Unexpected payload: %q", result.Payload)
+	}
+}
+
+func TestEncryptWithJWKNotPermitted(t *testing.T) {
+	jwkKey, err := jwk.GenerateKey(jwa.RSAOAEP256, 2048, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	token := jwe.NewEncryptedToken(jwa.RSAOAEP256, jwa.A256GCM, []byte("foo"))
+
+	sigKey := *jwkKey
+	sigKey.Usage = "sig"
+	_, err = token.Encrypt(&sigKey)
+	if _, ok := err.(jwk.ErrOperationNotPermitted); !ok {
+		t.Errorf("Signature key should not encrypt tokens: %v", err)
+	}
+
+	wrapKey := *jwkKey
+	wrapKey.KeyOps = []string{jwk.KeyOpWrapKey}
+	str, err := token.Encrypt(&wrapKey)
+	if err != nil {
+		t.Fatalf("Error encrypting token: %v", err)
+	}
+	_, err = jwe.Decrypt(str, func(h *jwe.RegHeader) (interface{}, error) {
+		return &wrapKey, nil
+	})
+	if _, ok := err.(jwk.ErrOperationNotPermitted); !ok {
+		t.Errorf("Key without unwrapKey should not decrypt tokens: %v", err)
+	}
+
+	otherKey := *jwkKey
+	otherKey.Algorithm = jwa.RSAOAEP
+	_, err = token.Encrypt(&otherKey)
+	if _, ok := err.(jwe.ErrUnexpectedAlg); !ok {
+		t.Errorf("Key of another algorithm should not encrypt tokens: %v", err)
+	}
+}
+
+func TestUnsupportedCompression(t *testing.T) {
+	key := loadKey(t)
+	token := jwe.NewEncryptedToken(jwa.RSAOAEP, jwa.A128GCM, []byte("foo"))
+	token.Header.Compression = "DEF"
+
+	if _, err := token.Encrypt(&key.PublicKey); err == nil {
+		t.Error("Compressed tokens should not be supported")
+	}
+}
+
+func TestDecryptCritical(t *testing.T) {
+	key := loadKey(t)
+	getKey := func(h *jwe.RegHeader) (interface{}, error) {
+		return key, nil
+	}
+
+	for _, crit := range [][]string{{"exp"}, {"alg"}} {
+		token := jwe.NewEncryptedToken(jwa.RSAOAEP, jwa.A128GCM, []byte("foo"))
+		token.Header.Critical = crit
+		str, err := token.Encrypt(&key.PublicKey)
+		if err != nil {
+			t.Fatalf("Error encrypting token: %v", err)
+		}
+
+		_, err = jwe.Decrypt(str, getKey)
+		if _, ok := err.(jws.ErrCritical); !ok {
+			t.Errorf("Unexpected error for critical %v: %v", crit, err)
+		}
+	}
+}
+
+func loadKey(t *testing.T) *rsa.PrivateKey {
+	var k jwk.Key
+	if err := json.Unmarshal([]byte(rfcKey), &k); err != nil {
+		t.Fatalf("Error decoding JWK: %v", err)
+	}
+
+	key, err := k.Key()
+	if err != nil {
+		t.Fatalf("Error getting raw key: %v", err)
+	}
+
+	return key.(*rsa.PrivateKey)
+}
+
+func flipFirst(seg string) string {
+	c := 'A'
+	if seg[0] == 'A' {
+		c = 'B'
+	}
+	return string(c) + seg[1:]
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe
+
+import (
+	"fmt"
+)
+
+// An ErrDecryption represents an error when token content could not be
+// decrypted or authenticated.
+type ErrDecryption string
+
+// Error returns string representation of current instance error.
+func (e ErrDecryption) Error() string {
+	return "The token content could not be decrypted"
+}
+
+// An ErrGetKey represents an error when was unable to retrieve token
+// decryption key.
+type ErrGetKey string
+
+// Error returns string representation of current instance error.
+func (e ErrGetKey) Error() string {
+	return "Error getting the decryption key for token"
+}
+
+// An ErrInvalidFormat represents an error when token format is invalid.
+type ErrInvalidFormat string
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidFormat) Error() string {
+	return "The format of provided token is invalid"
+}
+
+// An ErrNotNested represents an error when a token was expected to contain a
+// nested JWT but defines a different content type.
+type ErrNotNested string
+
+// Error returns string representation of current instance error.
+func (e ErrNotNested) Error() string {
+	return fmt.Sprintf(
+		"The token does not contain a nested JWT: content type '%s'",
+		string(e))
+}
+
+// An ErrUnexpectedAlg represents an error when token algorithm is not allowed
+// for current operation.
+type ErrUnexpectedAlg string
+
+// Error returns string representation of current instance error.
+func (e ErrUnexpectedAlg) Error() string {
+	return fmt.Sprintf("Unexpected token algorithm: %s", string(e))
+}
+
+// An ErrUnsupportedHeader represents an error when token header defines a
+// parameter which is not supported by current implementation.
+type ErrUnsupportedHeader string
+
+// Error returns string representation of current instance error.
+func (e ErrUnsupportedHeader) Error() string {
+	return fmt.Sprintf("Unsupported header parameter: %s", string(e))
+}
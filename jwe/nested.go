@@ -0,0 +1,71 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe
+
+import (
+	"strings"
+
+	"github.com/raiqub/jose/jws"
+	"github.com/raiqub/jose/jwt"
+)
+
+// ContentTypeJWT defines the content type of a JWE token whose payload is a
+// signed JWT (nested JWT).
+const ContentTypeJWT = "JWT"
+
+// EncryptSigned signs specified token using sigKey and then encrypts the
+// result using header algorithms and encKey, producing a nested JWT.
+func EncryptSigned(
+	signed *jws.SignedToken,
+	sigKey interface{},
+	header *RegHeader,
+	encKey interface{},
+) (string, error) {
+	inner, err := signed.EncodeAndSign(sigKey)
+	if err != nil {
+		return "", err
+	}
+
+	header.ContentType = ContentTypeJWT
+	token := &EncryptedToken{header, []byte(inner)}
+
+	return token.Encrypt(encKey)
+}
+
+// DecryptAndValidate decrypts a nested JWT and then decodes and validates the
+// signed token within it as jws.DecodeAndValidate does.
+func DecryptAndValidate(
+	token string,
+	getDecKey GetKeyFunc,
+	header jws.Header,
+	payload jwt.Claims,
+	getSigKey jws.GetKeyFunc,
+) (*jws.SignedToken, error) {
+	encrypted, err := Decrypt(token, getDecKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Content type value is case-insensitive as stated by JWT specification.
+	// Ref: https://tools.ietf.org/html/rfc7519#section-5.2
+	if !strings.EqualFold(encrypted.Header.ContentType, ContentTypeJWT) {
+		return nil, ErrNotNested(encrypted.Header.ContentType)
+	}
+
+	return jws.DecodeAndValidate(
+		string(encrypted.Payload), header, payload, getSigKey)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/hmac"
+	"github.com/raiqub/jose/jwe"
+	"github.com/raiqub/jose/jws"
+	"github.com/raiqub/jose/jwt"
+)
+
+func TestNestedToken(t *testing.T) {
+	key := loadKey(t)
+	sigKey := []byte("0123456789abcdef0123456789abcdef")
+
+	signed := jws.NewSignedToken(jwa.HS256)
+	claims := signed.Payload.(*jwt.CommonClaims)
+	claims.Subject = "john.doe"
+	claims.SetExpireAt(time.Now().Add(time.Minute))
+
+	header := jwe.NewHeader(jwa.RSAOAEP256, jwa.A256GCM)
+	str, err := jwe.EncryptSigned(signed, sigKey, header, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("Error creating nested token: %v", err)
+	}
+
+	getDecKey := func(h *jwe.RegHeader) (interface{}, error) {
+		return key, nil
+	}
+	getSigKey := func(h jws.Header) (interface{}, error) {
+		return sigKey, nil
+	}
+
+	result, err := jwe.DecryptAndValidate(str, getDecKey, nil, nil, getSigKey)
+	if err != nil {
+		t.Fatalf("Error validating nested token: %v", err)
+	}
+	if sub := result.Payload.(*jwt.CommonClaims).Subject; sub != "john.doe" {
+		t.Errorf("Unexpected subject: %s", sub)
+	}
+
+	_, err = jwe.DecryptAndValidate(str, getDecKey, nil, nil,
+		func(h jws.Header) (interface{}, error) {
+			return []byte("wrong key"), nil
+		})
+	if _, ok := err.(jws.ErrInvalidSignature); !ok {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+
+	plain := jwe.NewEncryptedToken(jwa.RSAOAEP256, jwa.A256GCM, []byte("{}"))
+	str, err = plain.Encrypt(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Error encrypting token: %v", err)
+	}
+	_, err = jwe.DecryptAndValidate(str, getDecKey, nil, nil, getSigKey)
+	if _, ok := err.(jwe.ErrNotNested); !ok {
+		t.Errorf("Expected not nested error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe
+
+import (
+	"strings"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/raiqub/jose/jwk"
+)
+
+// Content types of encrypted keys as defined by JWK specification.
+// Ref: https://tools.ietf.org/html/rfc7517#section-8.5
+const (
+	ContentTypeJWK    = "jwk+json"
+	ContentTypeJWKSet = "jwk-set+json"
+)
+
+// EncryptKey encrypts specified key using a password and the given PBES2 key
+// management and content encryption algorithms.
+func EncryptKey(key *jwk.Key, password []byte, alg, enc string) (string, error) {
+	return encryptWithPassword(key, ContentTypeJWK, password, alg, enc)
+}
+
+// DecryptKey decrypts a key encrypted by EncryptKey using specified password.
+func DecryptKey(token string, password []byte) (*jwk.Key, error) {
+	key := &jwk.Key{}
+	if err := decryptWithPassword(token, password, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// EncryptSet encrypts specified key set using a password and the given PBES2
+// key management and content encryption algorithms.
+func EncryptSet(set *jwk.Set, password []byte, alg, enc string) (string, error) {
+	return encryptWithPassword(set, ContentTypeJWKSet, password, alg, enc)
+}
+
+// DecryptSet decrypts a key set encrypted by EncryptSet using specified
+// password.
+func DecryptSet(token string, password []byte) (*jwk.Set, error) {
+	set := &jwk.Set{}
+	if err := decryptWithPassword(token, password, set); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func encryptWithPassword(
+	v interface{},
+	cty string,
+	password []byte,
+	alg, enc string,
+) (string, error) {
+	if !isPasswordAlg(alg) {
+		return "", ErrUnexpectedAlg(alg)
+	}
+
+	payload, err := ffjson.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	token := NewEncryptedToken(alg, enc, payload)
+	token.Header.ContentType = cty
+
+	return token.Encrypt(password)
+}
+
+func decryptWithPassword(token string, password []byte, v interface{}) error {
+	result, err := Decrypt(token, func(h *RegHeader) (interface{}, error) {
+		if !isPasswordAlg(h.GetAlgorithm()) {
+			return nil, ErrUnexpectedAlg(h.GetAlgorithm())
+		}
+
+		return password, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return ffjson.Unmarshal(result.Payload, v)
+}
+
+// isPasswordAlg reports whether specified algorithm is a password-based key
+// management algorithm.
+func isPasswordAlg(alg string) bool {
+	return strings.HasPrefix(alg, "PBES2-")
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe_test
+
+import (
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/pbes2"
+	"github.com/raiqub/jose/jwe"
+	"github.com/raiqub/jose/jwk"
+)
+
+const (
+	// Password from RFC 7517 Appendix C.
+	rfcPassword = "Thus from my lips, by yours, my sin is purged."
+
+	// Token generated by go-jose using rfcPassword.
+	pbes2Token = "eyJhbGciOiJQQkVTMi1IUzI1NitBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2IiwicDJjIjo0MDk2LCJwMnMiOiJEMnFuUHlsZFFxdUlOblp3eWF0bWNRIn0.xCrwu6IRBzxs3M2-GnRLxNA494kq63ssUR8hQJsuRt3WaHiGquNQFQ.5btKIT02oVntS11Azjpz4Q.1Y9F5UMkIa_W9BzLHLW2gfo0XNhv_YJBdNsasr-R4PQ.mgtTbUawncqPbCPieIEg7g"
+)
+
+func TestDecryptPBES2(t *testing.T) {
+	token, err := jwe.Decrypt(pbes2Token,
+		func(h *jwe.RegHeader) (interface{}, error) {
+			return []byte(rfcPassword), nil
+		})
+	if err != nil {
+		t.Fatalf("Error decrypting token: %v", err)
+	}
+	if token.Header.Iterations != 4096 {
+		t.Errorf("Unexpected iteration count: %d", token.Header.Iterations)
+	}
+	if string(token.Payload) != "Live long and prosper." {
+		t.Errorf("Unexpected payload: %q", token.Payload)
+	}
+}
+
+func TestEncryptAndDecryptSet(t *testing.T) {
+	var set jwk.Set
+	for alg, bits := range map[string]int{jwa.RSAOAEP: 2048, jwa.A128KW: 128} {
+		key, err := jwk.GenerateKey(alg, bits, 1)
+		if err != nil {
+			t.Fatalf("Error generating key: %v", err)
+		}
+		set.Keys = append(set.Keys, *key)
+	}
+
+	password := []byte("correct horse battery staple")
+	for _, alg := range []string{
+		jwa.PBES2HS256A128KW, jwa.PBES2HS384A192KW, jwa.PBES2HS512A256KW,
+	} {
+		str, err := jwe.EncryptSet(&set, password, alg, jwa.A256GCM)
+		if err != nil {
+			t.Fatalf("Error encrypting set (%s): %v", alg, err)
+		}
+
+		result, err := jwe.DecryptSet(str, password)
+		if err != nil {
+			t.Fatalf("Error decrypting set (%s): %v", alg, err)
+		}
+		if len(result.Keys) != len(set.Keys) {
+			t.Fatalf("Unexpected number of keys: %d", len(result.Keys))
+		}
+		for i := range set.Keys {
+			if result.Keys[i].ID != set.Keys[i].ID ||
+				result.Keys[i].D != set.Keys[i].D ||
+				result.Keys[i].K != set.Keys[i].K {
+				t.Errorf("Decrypted key %d does not match", i)
+			}
+		}
+
+		_, err = jwe.DecryptSet(str, []byte("wrong password"))
+		if _, ok := err.(jwe.ErrDecryption); !ok {
+			t.Errorf("Expected decryption error, got %v", err)
+		}
+	}
+}
+
+func TestEncryptAndDecryptKey(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ECDHES, 256, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	password := []byte(rfcPassword)
+	str, err := jwe.EncryptKey(key, password,
+		jwa.PBES2HS256A128KW, jwa.A128CBCHS256)
+	if err != nil {
+		t.Fatalf("Error encrypting key: %v", err)
+	}
+
+	result, err := jwe.DecryptKey(str, password)
+	if err != nil {
+		t.Fatalf("Error decrypting key: %v", err)
+	}
+	if result.ID != key.ID || result.D != key.D {
+		t.Error("Decrypted key does not match")
+	}
+
+	if _, err := jwe.EncryptKey(key, password,
+		jwa.Direct, jwa.A128GCM); err == nil {
+		t.Error("Non password-based algorithm should not be allowed")
+	}
+
+	// A token encrypted using the password as a direct key must be refused
+	token := jwe.NewEncryptedToken(jwa.Direct, jwa.A128GCM, []byte("{}"))
+	str, err = token.Encrypt([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Error encrypting token: %v", err)
+	}
+	_, err = jwe.DecryptKey(str, []byte("0123456789abcdef"))
+	if _, ok := err.(jwe.ErrUnexpectedAlg); !ok {
+		t.Errorf("Expected unexpected algorithm error, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwe
+
+import (
+	"encoding/base64"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwk"
+)
+
+// A RegHeader represents the JOSE header of a JWE token with all registered
+// parameter names.
+type RegHeader struct {
+	ID          string   `json:"kid,omitempty"`
+	Type        string   `json:"typ,omitempty"`
+	ContentType string   `json:"cty,omitempty"`
+	Algorithm   string   `json:"alg"`
+	Encryption  string   `json:"enc"`
+	Compression string   `json:"zip,omitempty"`
+	JWKSetURL   string   `json:"jku,omitempty"`
+	Critical    []string `json:"crit,omitempty"`
+
+	// Key agreement parameters
+
+	EphemeralKey *jwk.Key `json:"epk,omitempty"`
+	PartyUInfo   string   `json:"apu,omitempty"`
+	PartyVInfo   string   `json:"apv,omitempty"`
+
+	// Password-based encryption parameters
+
+	Salt       string `json:"p2s,omitempty"`
+	Iterations int    `json:"p2c,omitempty"`
+}
+
+// Names of header parameters registered by RFC 7516 and RFC 7518, which must
+// not be listed as critical.
+// Ref: https://tools.ietf.org/html/rfc7516#section-4.1.
+var registeredParams = map[string]bool{
+	"alg": true, "enc": true, "zip": true, "jku": true, "jwk": true,
+	"kid": true, "x5u": true, "x5c": true, "x5t": true, "x5t#S256": true,
+	"typ": true, "cty": true, "crit": true, "epk": true, "apu": true,
+	"apv": true, "iv": true, "tag": true, "p2s": true, "p2c": true,
+}
+
+// NewHeader creates a new instance of RegHeader type.
+func NewHeader(alg, enc string) *RegHeader {
+	return &RegHeader{
+		Algorithm:  alg,
+		Encryption: enc,
+	}
+}
+
+// GetID returns the identifier of the key used to encrypt current token.
+func (h *RegHeader) GetID() string {
+	return h.ID
+}
+
+// GetAlgorithm returns the algorithm used to encrypt or determine the content
+// encryption key of current token.
+func (h *RegHeader) GetAlgorithm() string {
+	return h.Algorithm
+}
+
+// GetEncryption returns the algorithm used to encrypt the content of current
+// token.
+func (h *RegHeader) GetEncryption() string {
+	return h.Encryption
+}
+
+// GetJWKSetURL returns a URL to retrieve the key used to encrypt current
+// token.
+func (h *RegHeader) GetJWKSetURL() string {
+	return h.JWKSetURL
+}
+
+// keyParams returns the parameters used by key management algorithm.
+func (h *RegHeader) keyParams() (*jwa.KeyParams, error) {
+	p := &jwa.KeyParams{
+		Encryption: h.Encryption,
+	}
+
+	var err error
+	if h.EphemeralKey != nil {
+		if p.EphemeralKey, err = h.EphemeralKey.Key(); err != nil {
+			return nil, err
+		}
+	}
+	if p.PartyUInfo, err = base64.RawURLEncoding.DecodeString(
+		h.PartyUInfo); err != nil {
+		return nil, err
+	}
+	if p.PartyVInfo, err = base64.RawURLEncoding.DecodeString(
+		h.PartyVInfo); err != nil {
+		return nil, err
+	}
+	if p.Salt, err = base64.RawURLEncoding.DecodeString(h.Salt); err != nil {
+		return nil, err
+	}
+	p.Iterations = h.Iterations
+
+	return p, nil
+}
+
+// setKeyParams sets the parameters defined by key management algorithm.
+func (h *RegHeader) setKeyParams(p *jwa.KeyParams) error {
+	h.Encryption = p.Encryption
+	h.Salt = base64.RawURLEncoding.EncodeToString(p.Salt)
+	h.Iterations = p.Iterations
+
+	if p.EphemeralKey != nil {
+		epk, err := jwk.NewKey(p.EphemeralKey)
+		if err != nil {
+			return err
+		}
+
+		epk.RemovePrivateFields()
+		h.EphemeralKey = epk
+	}
+
+	return nil
+}
@@ -16,7 +16,14 @@
 
 package adapters
 
-import "github.com/raiqub/jose/jwk"
+import (
+	"errors"
+
+	"github.com/raiqub/jose/jwk"
+)
+
+// ErrKeyNotFound defines an error when requested key was not found.
+var ErrKeyNotFound = errors.New("Key not found")
 
 // A Set represents a data adapter for JWK key set.
 type Set interface {
@@ -28,4 +35,7 @@ type Set interface {
 
 	// ByID returns a key by its identifier.
 	ByID(string) (*jwk.Key, error)
+
+	// ByThumbprintURI returns a key by its JWK thumbprint URI (RFC 9278).
+	ByThumbprintURI(string) (*jwk.Key, error)
 }
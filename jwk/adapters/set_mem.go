@@ -17,14 +17,11 @@
 package adapters
 
 import (
-	"errors"
+	"time"
 
 	"github.com/raiqub/jose/jwk"
 )
 
-// ErrKeyNotFound defines an error when requested key was not found.
-var ErrKeyNotFound = errors.New("Key not found")
-
 // A SetMemory represents an in-memory data adapter for JWK key set.
 type SetMemory struct {
 	keys map[string]jwk.Key
@@ -43,11 +40,14 @@ func (s *SetMemory) Add(key jwk.Key) error {
 	return nil
 }
 
-// All returns all keys.
+// All returns all keys which are currently valid.
 func (s *SetMemory) All() (*jwk.Set, error) {
+	now := time.Now()
 	var keys []jwk.Key
 	for _, k := range s.keys {
-		keys = append(keys, k)
+		if k.IsValidAt(now) {
+			keys = append(keys, k)
+		}
 	}
 
 	return &jwk.Set{
@@ -59,10 +59,25 @@ func (s *SetMemory) All() (*jwk.Set, error) {
 func (s *SetMemory) ByID(id string) (*jwk.Key, error) {
 	res, ok := s.keys[id]
 	if !ok {
-		return nil, errors.New("Key not found")
+		return nil, ErrKeyNotFound
 	}
 
 	return &res, nil
 }
 
+// ByThumbprintURI returns a key by its JWK thumbprint URI.
+func (s *SetMemory) ByThumbprintURI(uri string) (*jwk.Key, error) {
+	if _, _, err := jwk.ParseThumbprintURI(uri); err != nil {
+		return nil, err
+	}
+
+	for _, k := range s.keys {
+		if ok, _ := k.MatchThumbprintURI(uri); ok {
+			return &k, nil
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
 var _ Set = (*SetMemory)(nil)
@@ -24,11 +24,20 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-// A SetMongo represents a MongoDB data adapter for JWK key set.
+// A SetMongo represents a MongoDB data adapter for JWK key set. Keys are stored
+// along with their JWK thumbprint URIs, except for keys added before
+// thumbprint URIs were supported.
 type SetMongo struct {
 	col *mgo.Collection
 }
 
+// A mongoKey represents a key as stored in database, along with its JWK
+// thumbprint URIs so that keys can be found by them using an index.
+type mongoKey struct {
+	jwk.Key     `bson:",inline"`
+	Thumbprints []string `bson:"thumbprints"`
+}
+
 // NewSetMongo creates a new instance of SetMongo.
 func NewSetMongo(col *mgo.Collection) *SetMongo {
 	return &SetMongo{
@@ -36,20 +45,45 @@ func NewSetMongo(col *mgo.Collection) *SetMongo {
 	}
 }
 
+// EnsureIndex creates the index used to find keys by their JWK thumbprint
+// URIs, when it does not exist yet.
+func (s *SetMongo) EnsureIndex() error {
+	return s.col.EnsureIndex(mgo.Index{
+		Key:        []string{"thumbprints"},
+		Background: true,
+	})
+}
+
 // Add a new key to database.
 func (s *SetMongo) Add(key jwk.Key) error {
-	return s.col.Insert(key)
+	uris, err := key.ThumbprintURIs()
+	if err != nil {
+		return err
+	}
+
+	return s.col.Insert(mongoKey{key, uris})
 }
 
-// All returns all keys.
+// All returns all keys which are currently valid. Undefined validity bounds
+// do not restrict the validity of a key, as defined by jwk.Key.IsValidAt.
 func (s *SetMongo) All() (*jwk.Set, error) {
+	now := time.Now()
 	var keys []jwk.Key
 	err := s.col.Find(bson.M{
-		"nbf": bson.M{"$lte": time.Now()},
-		"exp": bson.M{"$gt": time.Now()},
-		"kty": bson.M{"$in": []string{jwk.KeyTypeECDSA, jwk.KeyTypeRSA}},
+		"$and": []bson.M{
+			{"$or": []bson.M{
+				{"nbf": bson.M{"$exists": false}},
+				{"nbf": bson.M{"$lte": now}},
+			}},
+			{"$or": []bson.M{
+				{"exp": bson.M{"$exists": false}},
+				{"exp": bson.M{"$gt": now}},
+			}},
+		},
+		"kty": bson.M{"$in": []string{
+			jwk.KeyTypeECDSA, jwk.KeyTypeRSA, jwk.KeyTypeOKP}},
 	}).Select(bson.M{
-		"kty": 1, "alg": 1, "use": 1,
+		"kty": 1, "alg": 1, "use": 1, "key_ops": 1, "nbf": 1, "exp": 1,
 		"crv": 1, "x": 1, "y": 1,
 		"n": 1, "e": 1,
 	}).All(&keys)
@@ -68,10 +102,57 @@ func (s *SetMongo) ByID(id string) (*jwk.Key, error) {
 	if err := s.col.
 		FindId(id).
 		One(&dbKey); err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 
 	return &dbKey, nil
 }
 
+// ByThumbprintURI returns a key by its JWK thumbprint URI, which must use a
+// hash function that was available when the key was added. Keys stored before
+// thumbprint URIs were indexed have no "thumbprints" field, thus when no
+// indexed key is found their thumbprints are computed instead, which requires
+// reading all of them.
+func (s *SetMongo) ByThumbprintURI(uri string) (*jwk.Key, error) {
+	if _, _, err := jwk.ParseThumbprintURI(uri); err != nil {
+		return nil, err
+	}
+
+	var dbKey jwk.Key
+	err := s.col.
+		Find(bson.M{"thumbprints": uri}).
+		One(&dbKey)
+	if err != mgo.ErrNotFound {
+		if err != nil {
+			return nil, err
+		}
+		return &dbKey, nil
+	}
+
+	iter := s.col.
+		Find(bson.M{"thumbprints": bson.M{"$exists": false}}).
+		Iter()
+	for iter.Next(&dbKey) {
+		if ok, _ := dbKey.MatchThumbprintURI(uri); ok {
+			iter.Close()
+			return &dbKey, nil
+		}
+		dbKey = jwk.Key{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// notFound translates the error returned when no document is found into
+// ErrKeyNotFound.
+func notFound(err error) error {
+	if err == mgo.ErrNotFound {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
 var _ Set = (*SetMongo)(nil)
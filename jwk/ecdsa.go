@@ -21,6 +21,7 @@ import (
 	"crypto/elliptic"
 
 	"github.com/raiqub/jose/converters"
+	"github.com/raiqub/jose/jwa/secp256k1"
 )
 
 func (k *Key) setECDSA(pub *ecdsa.PublicKey, priv *ecdsa.PrivateKey) error {
@@ -37,7 +38,7 @@ func (k *Key) setECDSA(pub *ecdsa.PublicKey, priv *ecdsa.PrivateKey) error {
 	k.Y = converters.Base64.FromBigInt(pub.Y, size)
 
 	if priv != nil {
-		k.D = converters.Base64.FromBigInt(priv.D, 0)
+		k.D = converters.Base64.FromBigInt(priv.D, size)
 	}
 
 	return nil
@@ -89,6 +90,8 @@ func curveFromName(cname string) (elliptic.Curve, error) {
 		return elliptic.P384(), nil
 	case "P-521":
 		return elliptic.P521(), nil
+	case "secp256k1":
+		return secp256k1.S256(), nil
 	default:
 		return nil, ErrUnsupportedEC(cname)
 	}
@@ -103,6 +106,8 @@ func curveName(crv elliptic.Curve) (string, error) {
 		return "P-384", nil
 	case elliptic.P521():
 		return "P-521", nil
+	case secp256k1.S256():
+		return "secp256k1", nil
 	default:
 		return "", ErrUnsupportedEC("?")
 	}
@@ -17,6 +17,7 @@
 package jwk
 
 import (
+	"crypto"
 	"fmt"
 )
 
@@ -43,6 +44,46 @@ func (e ErrIncompatibleAlg) Error() string {
 		e.Algorithm, e.Type)
 }
 
+// An ErrInvalidKeyData represents an error when the key material is malformed
+// or inconsistent with its declared type.
+type ErrInvalidKeyData string
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidKeyData) Error() string {
+	return fmt.Sprintf("Invalid key data: %s", string(e))
+}
+
+// An ErrInvalidThumbprintURI represents an error when a JWK thumbprint URI is
+// malformed or uses an unsupported hash function.
+type ErrInvalidThumbprintURI string
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidThumbprintURI) Error() string {
+	return fmt.Sprintf("Invalid JWK thumbprint URI: %s", string(e))
+}
+
+// An ErrOperationNotPermitted represents an error when a key is used for an
+// operation which is not permitted by its "use" or "key_ops" members.
+type ErrOperationNotPermitted struct {
+	KeyID     string
+	Operation string
+}
+
+// Error returns string representation of current instance error.
+func (e ErrOperationNotPermitted) Error() string {
+	return fmt.Sprintf("The operation '%s' is not permitted for key '%s'",
+		e.Operation, e.KeyID)
+}
+
+// An ErrUnavailableHash represents an error when specified hash function is
+// not linked into the binary.
+type ErrUnavailableHash crypto.Hash
+
+// Error returns string representation of current instance error.
+func (e ErrUnavailableHash) Error() string {
+	return fmt.Sprintf("Unavailable hash function: %s", crypto.Hash(e))
+}
+
 // An ErrUnknownType represents an error when the type specified for JWK key is
 // not supported by current implementation.
 type ErrUnknownType string
@@ -60,3 +101,12 @@ type ErrUnsupportedEC string
 func (e ErrUnsupportedEC) Error() string {
 	return fmt.Sprintf("Unsupported elliptic curve: %s", string(e))
 }
+
+// An ErrUnsupportedPEM represents an error when a key type is not supported for
+// PEM encoding.
+type ErrUnsupportedPEM string
+
+// Error returns string representation of current instance error.
+func (e ErrUnsupportedPEM) Error() string {
+	return fmt.Sprintf("Unsupported PEM encoding: %s", string(e))
+}
@@ -14,13 +14,14 @@
  * limitations under the License.
  */
 
-//go:generate ffjson $GOFILE
-
 package jwk
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"strings"
 	"time"
 
 	"github.com/raiqub/jose/jwa"
@@ -35,24 +36,34 @@ const (
 
 	// KeyTypeSymmetric defines the type code for symmetric keys.
 	KeyTypeSymmetric = "oct"
+
+	// KeyTypeOKP defines the type code for Octet Key Pair keys (RFC 8037).
+	KeyTypeOKP = "OKP"
 )
 
 type (
 	// A Set represents a set of keys as defined by JWK specification.
+	//
+	// ffjson: skip
 	Set struct {
 		Keys []Key `json:"keys"`
 	}
 
-	// A Key represents a key as defined by JWK specification.
+	// A Key represents a key as defined by JWK specification. The validity
+	// window of the key is represented in JSON by private "nbf" and "exp"
+	// members, which are defined as NumericDate values like JWT claims.
+	//
+	// ffjson: skip
 	Key struct {
-		ID        string    `bson:"_id" json:"kid"`
+		ID        string    `bson:"_id" json:"kid,omitempty"`
 		Type      string    `bson:"kty" json:"kty"`
-		Algorithm string    `bson:"alg" json:"alg"`
-		Usage     string    `bson:"use" json:"use"`
+		Algorithm string    `bson:"alg" json:"alg,omitempty"`
+		Usage     string    `bson:"use" json:"use,omitempty"`
+		KeyOps    []string  `bson:"key_ops,omitempty" json:"key_ops,omitempty"`
 		NotBefore time.Time `bson:"nbf,omitempty" json:"-"`
 		ExpireAt  time.Time `bson:"exp,omitempty" json:"-"`
 
-		// ECDSA
+		// ECDSA and OKP
 
 		Curve string `bson:"crv,omitempty" json:"crv,omitempty"`
 		X     string `bson:"x,omitempty" json:"x,omitempty"`
@@ -76,9 +87,17 @@ type (
 	}
 )
 
-// GenerateID creates a new identifier for current key.
+// GenerateID creates a new identifier for current key. The identifier is
+// derived from key thumbprint when defined by SetThumbprintIDGenerator, except
+// for symmetric keys whose thumbprint would disclose a hash of the secret.
 func (k *Key) GenerateID() error {
-	id, err := kidGen()
+	var id string
+	var err error
+	if kidHash != 0 && !k.IsSymmetric() {
+		id, err = ThumbprintID(k, kidHash)
+	} else {
+		id, err = kidGen()
+	}
 	if err != nil {
 		return err
 	}
@@ -102,6 +121,18 @@ func (k *Key) IsSymmetric() bool {
 	return k.Type == KeyTypeSymmetric
 }
 
+// IsOKP returns whether current key type is Octet Key Pair.
+func (k *Key) IsOKP() bool {
+	return k.Type == KeyTypeOKP
+}
+
+// IsValidAt returns whether current key is valid at specified instant in time.
+// Undefined NotBefore or ExpireAt do not restrict the validity of the key.
+func (k *Key) IsValidAt(t time.Time) bool {
+	return (k.NotBefore.IsZero() || !t.Before(k.NotBefore)) &&
+		(k.ExpireAt.IsZero() || t.Before(k.ExpireAt))
+}
+
 // Key creates a raw key instance based on current key specification.
 func (k *Key) Key() (interface{}, error) {
 	switch k.Type {
@@ -111,11 +142,24 @@ func (k *Key) Key() (interface{}, error) {
 		return k.getRSA()
 	case KeyTypeSymmetric:
 		return k.getSymmetric()
+	case KeyTypeOKP:
+		return k.getOKP()
 	default:
 		return nil, ErrUnknownType(k.Type)
 	}
 }
 
+// NewKey creates a new Key from specified raw key. Neither identifier nor
+// algorithm are defined for returned key.
+func NewKey(key interface{}) (*Key, error) {
+	var k Key
+	if err := k.setRaw(key); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
 // RemovePrivateFields discards all private information of current key.
 func (k *Key) RemovePrivateFields() {
 	k.D = ""
@@ -127,12 +171,50 @@ func (k *Key) RemovePrivateFields() {
 	k.K = ""
 }
 
-// SetKey parses specified raw key and sets current key to match it.
+// SetKey parses specified raw key and sets current key to match it. The key is
+// validated against specified algorithm.
 func (k *Key) SetKey(key interface{}, alg string) error {
-	if !jwa.Available(alg) {
+	if !jwa.Available(alg) && !jwa.KeyAlgorithmAvailable(alg) {
 		return jwa.ErrAlgUnavailable(alg)
 	}
 
+	if err := k.setRaw(key); err != nil {
+		return err
+	}
+
+	var compatible bool
+	switch k.Type {
+	case KeyTypeECDSA:
+		compatible = isSigningAlg(alg, "ES") || isECDHKeyAlg(alg)
+	case KeyTypeRSA:
+		compatible = isSigningAlg(alg, "RS") || isSigningAlg(alg, "PS") ||
+			isRSAKeyAlg(alg)
+	case KeyTypeSymmetric:
+		compatible = isSigningAlg(alg, "HS") || isSymmetricKeyAlg(alg)
+	case KeyTypeOKP:
+		compatible = (k.Curve == curveEd25519 && alg == jwa.EdDSA) ||
+			(k.Curve == curveX25519 && isECDHKeyAlg(alg))
+	}
+	if !compatible {
+		return ErrIncompatibleAlg{k.Type, alg}
+	}
+
+	k.Algorithm = alg
+	if err := k.Validate(); err != nil {
+		return err
+	}
+
+	if len(k.ID) == 0 {
+		if err := k.GenerateID(); err != nil {
+			return ErrGenID(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// setRaw sets current key parameters to match specified raw key.
+func (k *Key) setRaw(key interface{}) error {
 	var err error
 	switch keyCast := key.(type) {
 	case *ecdsa.PublicKey:
@@ -143,56 +225,81 @@ func (k *Key) SetKey(key interface{}, alg string) error {
 		err = k.setRSA(keyCast, nil)
 	case *rsa.PrivateKey:
 		err = k.setRSA(&keyCast.PublicKey, keyCast)
+	case ed25519.PublicKey:
+		err = k.setEd25519(keyCast, nil)
+	case ed25519.PrivateKey:
+		err = k.setEd25519(
+			keyCast.Public().(ed25519.PublicKey), keyCast)
+	case *ecdh.PublicKey:
+		err = k.setX25519(keyCast, nil)
+	case *ecdh.PrivateKey:
+		err = k.setX25519(keyCast.PublicKey(), keyCast)
 	case []byte:
 		err = k.setSymmetric(keyCast)
 	default:
 		return jwa.ErrInvalidKey{Value: key}
 	}
-	if err != nil {
-		return err
-	}
 
-	switch k.Type {
-	case KeyTypeECDSA:
-		if alg[:2] != "ES" {
-			return ErrIncompatibleAlg{k.Type, alg}
-		}
-	case KeyTypeRSA:
-		if alg[:2] != "RS" && alg[:2] != "PS" {
-			return ErrIncompatibleAlg{k.Type, alg}
-		}
-	case KeyTypeSymmetric:
-		if alg[:2] != "HS" {
-			return ErrIncompatibleAlg{k.Type, alg}
-		}
+	return err
+}
+
+// isSigningAlg reports whether specified algorithm is an available signing
+// algorithm of the family identified by specified prefix.
+func isSigningAlg(alg, prefix string) bool {
+	return jwa.Available(alg) && strings.HasPrefix(alg, prefix)
+}
+
+// isECDHKeyAlg reports whether specified key management algorithm uses an
+// elliptic curve key agreement.
+func isECDHKeyAlg(alg string) bool {
+	switch alg {
+	case jwa.ECDHES, jwa.ECDHESA128KW, jwa.ECDHESA192KW, jwa.ECDHESA256KW:
+		return true
 	}
+	return false
+}
 
-	if len(k.ID) == 0 {
-		if err := k.GenerateID(); err != nil {
-			return ErrGenID(err.Error())
-		}
+// isRSAKeyAlg reports whether specified key management algorithm encrypts
+// keys using RSA.
+func isRSAKeyAlg(alg string) bool {
+	switch alg {
+	case jwa.RSAOAEP, jwa.RSAOAEP256:
+		return true
 	}
+	return false
+}
 
-	k.Algorithm = alg
-	return nil
+// isSymmetricKeyAlg reports whether specified key management algorithm uses a
+// shared symmetric key.
+func isSymmetricKeyAlg(alg string) bool {
+	switch alg {
+	case jwa.A128KW, jwa.A192KW, jwa.A256KW, jwa.Direct:
+		return true
+	}
+	return false
 }
 
 // GenerateKey generates a key of the given algorithm, bit size and life
-// duration
+// duration. Key management algorithms generate keys intended for encryption.
 func GenerateKey(alg string, bits, days int) (*Key, error) {
-	method, err := jwa.New(alg)
-	if err != nil {
-		return nil, err
-	}
-
-	key, err := method.GenerateKey(bits)
-	if err != nil {
-		return nil, err
+	var key interface{}
+	usage := UsageSignature
+	if method, err := jwa.New(alg); err == nil {
+		if key, err = method.GenerateKey(bits); err != nil {
+			return nil, err
+		}
+	} else if method, err := jwa.NewKeyAlgorithm(alg); err == nil {
+		if key, err = method.GenerateKey(bits); err != nil {
+			return nil, err
+		}
+		usage = UsageEncryption
+	} else {
+		return nil, jwa.ErrAlgUnavailable(alg)
 	}
 
 	now := time.Now()
 	jwkKey := Key{
-		Usage:     "sig",
+		Usage:     usage,
 		NotBefore: now,
 		ExpireAt:  now.Add(time.Hour * 24 * time.Duration(days)),
 	}
@@ -17,13 +17,17 @@
 package jwk
 
 import (
+	"crypto"
 	"crypto/rand"
 	"io"
 
 	"github.com/raiqub/jose/converters"
 )
 
-var kidGen = DefaultKeyIDGenerator
+var (
+	kidGen  = DefaultKeyIDGenerator
+	kidHash crypto.Hash
+)
 
 // The KeyIDGenerator type is an adapter to allow to set custom identifiers
 // generator for keys.
@@ -45,4 +49,25 @@ func DefaultKeyIDGenerator() (string, error) {
 // SetIDGenerator defines a custom generator for key identifiers.
 func SetIDGenerator(f KeyIDGenerator) {
 	kidGen = f
+	kidHash = 0
+}
+
+// SetThumbprintIDGenerator defines that key identifiers are derived from the
+// JWK thumbprint of keys computed using specified hash function, therefore the
+// same key always gets the same identifier across systems. Symmetric keys
+// still get identifiers from the current generator, since their thumbprint is
+// a hash of the secret key and identifiers are published.
+func SetThumbprintIDGenerator(hash crypto.Hash) {
+	kidHash = hash
+}
+
+// ThumbprintID returns the base64url encoded JWK thumbprint of specified key
+// computed using specified hash function, as suitable for key identifier.
+func ThumbprintID(k *Key, hash crypto.Hash) (string, error) {
+	sum, err := k.Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return converters.Base64.FromBytes(sum), nil
 }
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// keyAlias defines a type having same fields as Key but no methods.
+type keyAlias Key
+
+// keyJSON defines the JSON representation of Key.
+type keyJSON struct {
+	*keyAlias
+	NotBefore int64 `json:"nbf,omitempty"`
+	ExpireAt  int64 `json:"exp,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of current key.
+func (k *Key) MarshalJSON() ([]byte, error) {
+	v := keyJSON{keyAlias: (*keyAlias)(k)}
+	if !k.NotBefore.IsZero() {
+		v.NotBefore = k.NotBefore.Unix()
+	}
+	if !k.ExpireAt.IsZero() {
+		v.ExpireAt = k.ExpireAt.Unix()
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON parses specified JSON representation of a key to current
+// instance.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	v := keyJSON{keyAlias: (*keyAlias)(k)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.NotBefore != 0 {
+		k.NotBefore = time.Unix(v.NotBefore, 0)
+	}
+	if v.ExpireAt != 0 {
+		k.ExpireAt = time.Unix(v.ExpireAt, 0)
+	}
+
+	return nil
+}
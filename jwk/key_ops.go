@@ -0,0 +1,138 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"strings"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+const (
+	// UsageSignature defines the "use" value for keys intended for signing
+	// and verifying signatures.
+	UsageSignature = "sig"
+
+	// UsageEncryption defines the "use" value for keys intended for
+	// encryption.
+	UsageEncryption = "enc"
+)
+
+// List of key operations as defined by JWK specification.
+// Ref: https://tools.ietf.org/html/rfc7517#section-4.3.
+const (
+	// KeyOpSign defines the operation to compute digital signature or MAC.
+	KeyOpSign = "sign"
+
+	// KeyOpVerify defines the operation to verify digital signature or MAC.
+	KeyOpVerify = "verify"
+
+	// KeyOpEncrypt defines the operation to encrypt content.
+	KeyOpEncrypt = "encrypt"
+
+	// KeyOpDecrypt defines the operation to decrypt content and validate
+	// decryption.
+	KeyOpDecrypt = "decrypt"
+
+	// KeyOpWrapKey defines the operation to encrypt key.
+	KeyOpWrapKey = "wrapKey"
+
+	// KeyOpUnwrapKey defines the operation to decrypt key and validate
+	// decryption.
+	KeyOpUnwrapKey = "unwrapKey"
+
+	// KeyOpDeriveKey defines the operation to derive key.
+	KeyOpDeriveKey = "deriveKey"
+
+	// KeyOpDeriveBits defines the operation to derive bits not to be used as
+	// a key.
+	KeyOpDeriveBits = "deriveBits"
+)
+
+// Key operations permitted by each intended use of a key.
+var usageOps = map[string][]string{
+	UsageSignature: {KeyOpSign, KeyOpVerify},
+	UsageEncryption: {KeyOpEncrypt, KeyOpDecrypt, KeyOpWrapKey,
+		KeyOpUnwrapKey, KeyOpDeriveKey, KeyOpDeriveBits},
+}
+
+// CheckOperation returns ErrOperationNotPermitted when specified operation is
+// not permitted by "use" or "key_ops" members of current key. Keys defining
+// neither members are permitted to any operation.
+func (k *Key) CheckOperation(op string) error {
+	if len(k.Usage) > 0 && !containsString(usageOps[k.Usage], op) {
+		return ErrOperationNotPermitted{k.ID, op}
+	}
+	if len(k.KeyOps) > 0 && !containsString(k.KeyOps, op) {
+		return ErrOperationNotPermitted{k.ID, op}
+	}
+
+	return nil
+}
+
+// CheckEncrypt returns ErrOperationNotPermitted when current key is not
+// permitted to encrypt tokens using its key management algorithm.
+func (k *Key) CheckEncrypt() error {
+	op, _ := encryptionOps(k.Algorithm)
+	return k.CheckOperation(op)
+}
+
+// CheckDecrypt returns ErrOperationNotPermitted when current key is not
+// permitted to decrypt tokens using its key management algorithm.
+func (k *Key) CheckDecrypt() error {
+	_, op := encryptionOps(k.Algorithm)
+	return k.CheckOperation(op)
+}
+
+// encryptionOps returns the key operations performed by specified key
+// management algorithm when encrypting and when decrypting tokens.
+func encryptionOps(alg string) (string, string) {
+	switch {
+	case alg == jwa.Direct:
+		return KeyOpEncrypt, KeyOpDecrypt
+	case strings.HasPrefix(alg, jwa.ECDHES):
+		return KeyOpDeriveKey, KeyOpDeriveKey
+	default:
+		return KeyOpWrapKey, KeyOpUnwrapKey
+	}
+}
+
+// validateOps checks whether "use" and "key_ops" members of current key are
+// consistent with each other.
+func (k *Key) validateOps() error {
+	permitted, ok := usageOps[k.Usage]
+	if len(k.Usage) == 0 || !ok {
+		return nil
+	}
+
+	for _, op := range k.KeyOps {
+		if !containsString(permitted, op) {
+			return ErrInvalidKeyData("key_ops inconsistent with use")
+		}
+	}
+
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
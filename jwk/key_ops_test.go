@@ -0,0 +1,95 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+func TestCheckOperation(t *testing.T) {
+	testCases := []struct {
+		key       Key
+		op        string
+		permitted bool
+	}{
+		{Key{}, KeyOpSign, true},
+		{Key{Usage: UsageSignature}, KeyOpSign, true},
+		{Key{Usage: UsageSignature}, KeyOpVerify, true},
+		{Key{Usage: UsageSignature}, KeyOpWrapKey, false},
+		{Key{Usage: UsageEncryption}, KeyOpSign, false},
+		{Key{Usage: UsageEncryption}, KeyOpUnwrapKey, true},
+		{Key{Usage: "other"}, KeyOpVerify, false},
+		{Key{KeyOps: []string{KeyOpVerify}}, KeyOpVerify, true},
+		{Key{KeyOps: []string{KeyOpVerify}}, KeyOpSign, false},
+		{Key{Usage: UsageSignature, KeyOps: []string{KeyOpVerify}},
+			KeyOpSign, false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.key.CheckOperation(tc.op)
+		if tc.permitted && err != nil {
+			t.Errorf("Case %d: unexpected error: %v", i, err)
+		}
+		if _, ok := err.(ErrOperationNotPermitted); !tc.permitted && !ok {
+			t.Errorf("Case %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestCheckEncryption(t *testing.T) {
+	testCases := []struct {
+		alg     string
+		ops     []string
+		encrypt bool
+		decrypt bool
+	}{
+		{jwa.RSAOAEP, []string{KeyOpWrapKey}, true, false},
+		{jwa.A128KW, []string{KeyOpUnwrapKey}, false, true},
+		{jwa.ECDHES, []string{KeyOpDeriveKey}, true, true},
+		{jwa.Direct, []string{KeyOpEncrypt, KeyOpDecrypt}, true, true},
+		{jwa.Direct, []string{KeyOpWrapKey, KeyOpUnwrapKey}, false, false},
+	}
+
+	for _, tc := range testCases {
+		key := Key{Algorithm: tc.alg, Usage: UsageEncryption, KeyOps: tc.ops}
+		if (key.CheckEncrypt() == nil) != tc.encrypt {
+			t.Errorf("Unexpected encryption result for %s %v", tc.alg, tc.ops)
+		}
+		if (key.CheckDecrypt() == nil) != tc.decrypt {
+			t.Errorf("Unexpected decryption result for %s %v", tc.alg, tc.ops)
+		}
+	}
+}
+
+func TestValidateKeyOps(t *testing.T) {
+	key, err := GenerateKey(jwa.HS256, 256, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	key.KeyOps = []string{KeyOpSign, KeyOpVerify}
+	if err := key.Validate(); err != nil {
+		t.Errorf("Unexpected error validating key: %v", err)
+	}
+
+	key.KeyOps = []string{KeyOpSign, KeyOpEncrypt}
+	if err := key.Validate(); err == nil {
+		t.Error("Inconsistent use and key_ops should be rejected")
+	}
+}
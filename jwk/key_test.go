@@ -17,13 +17,22 @@
 package jwk
 
 import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/aeskw"
+	_ "github.com/raiqub/jose/jwa/direct"
+	_ "github.com/raiqub/jose/jwa/ecdhes"
+	_ "github.com/raiqub/jose/jwa/eddsa"
 	_ "github.com/raiqub/jose/jwa/hmac"
+	_ "github.com/raiqub/jose/jwa/oaep"
 	_ "github.com/raiqub/jose/jwa/pkcs1"
 )
 
@@ -128,6 +137,29 @@ const (
      "k": "AAPapAv4LbFbiVawEjagUBluYqN5rhna-8nuldDvOx8"
    }`
 
+	// Examples from RFC 8037
+
+	// Ed25519 Private Key
+	okpEd25519Key = `{
+     "kty": "OKP",
+     "use": "sig",
+     "crv": "Ed25519",
+     "d": "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+     "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+   }`
+	// X25519 Private Key
+	okpX25519Key = `{
+     "kty": "OKP",
+     "use": "enc",
+     "crv": "X25519",
+     "d": "dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo",
+     "x": "hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"
+   }`
+
+	okpHeader    = `eyJhbGciOiJFZERTQSJ9`
+	okpPayload   = `RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc`
+	okpSignature = `hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg`
+
 	kid1 = "bilbo.baggins@hobbiton.example"
 	kid2 = "018c0ae5-4d9b-471b-bfd6-eef314bc7037"
 	use  = "sig"
@@ -256,3 +288,293 @@ func testSigning(name, header, encKey, algName, signature string, t *testing.T)
 		t.Error("Unexpected signature was generated")
 	}
 }
+
+func TestDecodeOKPKeys(t *testing.T) {
+	key := testDecodeKey(okpEd25519Key, "Ed25519 private", t)
+	if !key.IsOKP() {
+		t.Error("Decoded key should be OKP")
+	}
+	raw, _ := key.Key()
+	if _, ok := raw.(ed25519.PrivateKey); !ok {
+		t.Errorf("Unexpected raw key type: %T", raw)
+	}
+
+	var xKey Key
+	input := strings.Replace(okpX25519Key, "\n", "", -1)
+	if err := json.Unmarshal([]byte(input), &xKey); err != nil {
+		t.Fatalf("Error decoding X25519 key: %v", err)
+	}
+	raw, err := xKey.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw key from X25519 key: %v", err)
+	}
+	priv, ok := raw.(*ecdh.PrivateKey)
+	if !ok {
+		t.Fatalf("Unexpected raw key type: %T", raw)
+	}
+	if base64.RawURLEncoding.EncodeToString(priv.PublicKey().Bytes()) !=
+		xKey.X {
+		t.Error("X25519 public key doesn't match private key")
+	}
+}
+
+func TestSigningOKP(t *testing.T) {
+	key := testDecodeKey(okpEd25519Key, "EdDSA signing", t)
+	sigKey, _ := key.Key()
+
+	alg, err := jwa.New(jwa.EdDSA)
+	if err != nil {
+		t.Fatalf("Error loading algorithm: %v", err)
+	}
+
+	sig, err := alg.Sign(okpHeader+"."+okpPayload, sigKey)
+	if err != nil {
+		t.Fatalf("Error signing input: %v", err)
+	}
+	if sig != okpSignature {
+		t.Error("Unexpected signature was generated")
+	}
+}
+
+func TestOKPRoundTrip(t *testing.T) {
+	key, err := GenerateKey(jwa.EdDSA, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	raw, err := json.Marshal(&Set{Keys: []Key{*key}})
+	if err != nil {
+		t.Fatalf("Error encoding key set: %v", err)
+	}
+
+	var set Set
+	if err := json.Unmarshal(raw, &set); err != nil {
+		t.Fatalf("Error decoding key set: %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].ID != key.ID ||
+		set.Keys[0].X != key.X || set.Keys[0].D != key.D {
+		t.Fatal("Decoded key set doesn't match original key")
+	}
+
+	pub := set.Keys[0]
+	pub.RemovePrivateFields()
+	rawPub, err := pub.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw public key: %v", err)
+	}
+	if _, ok := rawPub.(ed25519.PublicKey); !ok {
+		t.Errorf("Unexpected raw key type: %T", rawPub)
+	}
+}
+
+func TestX25519RoundTrip(t *testing.T) {
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	var key Key
+	if err := key.SetKey(priv, jwa.EdDSA); err == nil {
+		t.Error("X25519 key should not be accepted for EdDSA")
+	}
+	if err := key.SetKey(priv, jwa.ECDHESA128KW); err != nil {
+		t.Fatalf("Error setting X25519 key: %v", err)
+	}
+
+	raw, err := json.Marshal(&key)
+	if err != nil {
+		t.Fatalf("Error encoding key: %v", err)
+	}
+
+	var decoded Key
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Error decoding key: %v", err)
+	}
+	if decoded.Curve != curveX25519 || decoded.Algorithm != jwa.ECDHESA128KW {
+		t.Errorf("Unexpected decoded key: %s", raw)
+	}
+
+	rawKey, err := decoded.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw key: %v", err)
+	}
+	if out, ok := rawKey.(*ecdh.PrivateKey); !ok || !out.Equal(priv) {
+		t.Error("Decoded key doesn't match original key")
+	}
+
+	ed, err := GenerateKey(jwa.EdDSA, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	rawEd, _ := ed.Key()
+	if err := new(Key).SetKey(rawEd, jwa.ECDHES); err == nil {
+		t.Error("Ed25519 key should not be accepted for ECDH-ES")
+	}
+}
+
+func TestSecp256k1RoundTrip(t *testing.T) {
+	// ES256K test vector produced by OpenSSL
+	const (
+		x     = "bX4iqX5s90OzBnKzSXf1bcYZ8h4OrA1gxputvukq46c"
+		y     = "3z69TWH-xoNwfy4B51-nhu8zAarC4KE7cL2e3EHR3cc"
+		d     = "En_qLPALjkf9KbVXVi5zly62AR_a3coOm5SqzCVQGJg"
+		input = "eyJhbGciOiJFUzI1NksifQ.eyJmb28iOiJiYXIifQ"
+		sig   = "bNwLEsYvmfUVRxaecYMlJtpvjjbhIF54bylsKw5vW7ByyIpI7BCsg3zPNnff" +
+			"bPfh7MczEg9e_WpEx_sdbPmrXg"
+	)
+
+	key := Key{Type: KeyTypeECDSA, Algorithm: jwa.ES256K, Curve: "secp256k1",
+		X: x, Y: y}
+	raw, err := json.Marshal(&key)
+	if err != nil {
+		t.Fatalf("Error encoding key: %v", err)
+	}
+
+	var decoded Key
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Error decoding key: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Fatalf("Error validating key: %v", err)
+	}
+
+	pubKey, err := decoded.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw key: %v", err)
+	}
+
+	alg, _ := jwa.New(jwa.ES256K)
+	if err := alg.Verify(input, sig, pubKey); err != nil {
+		t.Errorf("Error verifying signature: %v", err)
+	}
+
+	decoded.D = d
+	if err := decoded.Validate(); err != nil {
+		t.Fatalf("Error validating private key: %v", err)
+	}
+	decoded.D = "AQ"
+	if err := decoded.Validate(); err == nil {
+		t.Error("Mismatched private key passed validation")
+	}
+
+	generated, err := GenerateKey(jwa.ES256K, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	if err := generated.Validate(); err != nil {
+		t.Fatalf("Error validating generated key: %v", err)
+	}
+	sigKey, err := generated.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw key: %v", err)
+	}
+	signature, err := alg.Sign(input, sigKey)
+	if err != nil {
+		t.Fatalf("Error signing input: %v", err)
+	}
+	if err := alg.Verify(input, signature, sigKey); err != nil {
+		t.Errorf("Error verifying own signature: %v", err)
+	}
+}
+
+func TestSymmetricKeyManagement(t *testing.T) {
+	key, err := GenerateKey(jwa.A256KW, 256, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	if !key.IsSymmetric() || key.Usage != "enc" {
+		t.Errorf("Unexpected key type or usage: %s/%s", key.Type, key.Usage)
+	}
+
+	raw, err := key.Key()
+	if err != nil {
+		t.Fatalf("Error creating raw key: %v", err)
+	}
+	if len(raw.([]byte)) != 32 {
+		t.Errorf("Unexpected key size: %d", len(raw.([]byte)))
+	}
+
+	var dirKey Key
+	if err := dirKey.SetKey(raw, jwa.Direct); err != nil {
+		t.Errorf("Error setting direct key: %v", err)
+	}
+	if err := dirKey.SetKey(raw, jwa.RSAOAEP); err == nil {
+		t.Error("Symmetric key should not be compatible with RSA-OAEP")
+	}
+}
+
+func TestSetKeyAlgorithm(t *testing.T) {
+	rsaKey, _ := testDecodeKey(rsaPrivateKey, "RSA private", t).Key()
+	ecKey, _ := testDecodeKey(ecdsaPrivateKey, "EC private", t).Key()
+
+	testCases := []struct {
+		key   interface{}
+		alg   string
+		valid bool
+	}{
+		{rsaKey, jwa.RS256, true},
+		{rsaKey, jwa.RSAOAEP, true},
+		{rsaKey, jwa.RSAOAEP256, true},
+		{rsaKey, jwa.A128KW, false},
+		{rsaKey, jwa.ECDHES, false},
+		{ecKey, jwa.ECDHESA256KW, true},
+		{ecKey, jwa.RSAOAEP, false},
+		{ecKey, jwa.HS256, false},
+	}
+
+	for _, tc := range testCases {
+		var key Key
+		err := key.SetKey(tc.key, tc.alg)
+		if _, ok := err.(ErrIncompatibleAlg); ok == tc.valid {
+			t.Errorf("Unexpected error for %T with %s: %v",
+				tc.key, tc.alg, err)
+		}
+	}
+}
+
+func TestKeyValidityJSON(t *testing.T) {
+	key, err := GenerateKey(jwa.HS256, 256, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	data, err := json.Marshal(Set{Keys: []Key{*key}})
+	if err != nil {
+		t.Fatalf("Error encoding key set: %v", err)
+	}
+	if !strings.Contains(string(data), `"nbf":`) ||
+		!strings.Contains(string(data), `"exp":`) {
+		t.Errorf("The validity window should be encoded: %s", data)
+	}
+
+	var set Set
+	if err := json.Unmarshal(data, &set); err != nil {
+		t.Fatalf("Error decoding key set: %v", err)
+	}
+	decoded := set.Keys[0]
+	if decoded.NotBefore.Unix() != key.NotBefore.Unix() ||
+		decoded.ExpireAt.Unix() != key.ExpireAt.Unix() ||
+		decoded.K != key.K || decoded.ID != key.ID {
+		t.Errorf("Unexpected decoded key: %#v", decoded)
+	}
+
+	now := time.Now()
+	if !decoded.IsValidAt(now) {
+		t.Error("The key should be valid")
+	}
+	if decoded.IsValidAt(now.Add(-time.Minute)) ||
+		decoded.IsValidAt(now.Add(48*time.Hour)) {
+		t.Error("The key should not be valid outside its validity window")
+	}
+
+	var unbounded Key
+	if err := json.Unmarshal([]byte(symKey), &unbounded); err != nil {
+		t.Fatalf("Error decoding key: %v", err)
+	}
+	if !unbounded.IsValidAt(now) || !unbounded.NotBefore.IsZero() {
+		t.Error("A key without validity window should always be valid")
+	}
+	if data, _ := json.Marshal(&unbounded); strings.Contains(string(data), "nbf") {
+		t.Errorf("An undefined validity window should be omitted: %s", data)
+	}
+}
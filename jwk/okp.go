@@ -0,0 +1,121 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+
+	"github.com/raiqub/jose/converters"
+)
+
+const (
+	// Curve names for Octet Key Pair keys as defined by RFC 8037
+	curveEd25519 = "Ed25519"
+	curveX25519  = "X25519"
+)
+
+func (k *Key) setEd25519(pub ed25519.PublicKey, priv ed25519.PrivateKey) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return ErrInvalidKeyData("invalid Ed25519 public key size")
+	}
+
+	k.Type = KeyTypeOKP
+	k.Curve = curveEd25519
+	k.X = converters.Base64.FromBytes(pub)
+
+	if priv != nil {
+		if len(priv) != ed25519.PrivateKeySize {
+			return ErrInvalidKeyData("invalid Ed25519 private key size")
+		}
+		k.D = converters.Base64.FromBytes(priv.Seed())
+	}
+
+	return nil
+}
+
+func (k *Key) setX25519(pub *ecdh.PublicKey, priv *ecdh.PrivateKey) error {
+	if pub.Curve() != ecdh.X25519() {
+		return ErrUnsupportedEC("?")
+	}
+
+	k.Type = KeyTypeOKP
+	k.Curve = curveX25519
+	k.X = converters.Base64.FromBytes(pub.Bytes())
+
+	if priv != nil {
+		k.D = converters.Base64.FromBytes(priv.Bytes())
+	}
+
+	return nil
+}
+
+func (k *Key) getOKP() (interface{}, error) {
+	x, err := converters.Base64.ToBytes(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	var d []byte
+	if len(k.D) > 0 {
+		if d, err = converters.Base64.ToBytes(k.D); err != nil {
+			return nil, err
+		}
+	}
+
+	switch k.Curve {
+	case curveEd25519:
+		return getEd25519(x, d)
+	case curveX25519:
+		return getX25519(x, d)
+	default:
+		return nil, ErrUnsupportedEC(k.Curve)
+	}
+}
+
+func getEd25519(x, d []byte) (interface{}, error) {
+	if len(x) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKeyData("invalid Ed25519 public key size")
+	}
+	if d == nil {
+		return ed25519.PublicKey(x), nil
+	}
+
+	if len(d) != ed25519.SeedSize {
+		return nil, ErrInvalidKeyData("invalid Ed25519 private key size")
+	}
+
+	return ed25519.NewKeyFromSeed(d), nil
+}
+
+func getX25519(x, d []byte) (interface{}, error) {
+	if d == nil {
+		pub, err := ecdh.X25519().NewPublicKey(x)
+		if err != nil {
+			return nil, ErrInvalidKeyData(err.Error())
+		}
+
+		return pub, nil
+	}
+
+	priv, err := ecdh.X25519().NewPrivateKey(d)
+	if err != nil {
+		return nil, ErrInvalidKeyData(err.Error())
+	}
+
+	return priv, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+// PEM block types written by ToPEM.
+const (
+	pemPrivateKey = "PRIVATE KEY"
+	pemPublicKey  = "PUBLIC KEY"
+)
+
+// FromPEM creates a new Key from the first key found on PEM encoded data, which
+// may be a PKCS#1, PKCS#8 or SEC 1 private key, a PKIX or PKCS#1 public key or
+// a certificate. When an algorithm is specified the key is checked against it
+// and an identifier is generated, otherwise neither identifier nor algorithm
+// are defined for returned key.
+func FromPEM(data []byte, alg string) (*Key, error) {
+	return FromEncryptedPEM(data, nil, alg)
+}
+
+// FromEncryptedPEM creates a new Key from PEM encoded data like FromPEM,
+// decrypting encrypted PKCS#8 private keys using specified passphrase.
+func FromEncryptedPEM(data, passphrase []byte, alg string) (*Key, error) {
+	bundle, err := jwa.ParsePEM(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := bundle.Key("")
+	if err != nil {
+		return nil, err
+	}
+
+	var k Key
+	if len(alg) > 0 {
+		if err := k.SetKey(raw, alg); err != nil {
+			return nil, err
+		}
+
+		return &k, nil
+	}
+
+	if err := k.setRaw(raw); err != nil {
+		return nil, err
+	}
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// ToPEM encodes current key as PEM. Private keys are encoded as PKCS#8 and
+// public keys are encoded as PKIX. Symmetric keys have no PEM representation.
+func (k *Key) ToPEM() ([]byte, error) {
+	if k.IsSymmetric() {
+		return nil, ErrUnsupportedPEM(k.Type)
+	}
+
+	raw, err := k.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	var block pem.Block
+	if k.hasPrivateFields() {
+		block.Type = pemPrivateKey
+		block.Bytes, err = x509.MarshalPKCS8PrivateKey(raw)
+	} else {
+		block.Type = pemPublicKey
+		block.Bytes, err = x509.MarshalPKIXPublicKey(raw)
+	}
+	if err != nil {
+		return nil, ErrUnsupportedPEM(err.Error())
+	}
+
+	return pem.EncodeToMemory(&block), nil
+}
+
+// ToPublicPEM encodes the public part of current key as PKIX PEM.
+func (k *Key) ToPublicPEM() ([]byte, error) {
+	pub := *k
+	pub.RemovePrivateFields()
+	return pub.ToPEM()
+}
+
+// hasPrivateFields reports whether current key holds any private information.
+func (k *Key) hasPrivateFields() bool {
+	return len(k.D) > 0 || len(k.PrimeP) > 0 || len(k.PrimeQ) > 0 ||
+		len(k.K) > 0
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/ecdsa"
+)
+
+func TestFromPEMFiles(t *testing.T) {
+	testCases := []struct {
+		file    string
+		alg     string
+		private bool
+	}{
+		{"../jwa/rsa/test/sample_key", jwa.RS256, true},
+		{"../jwa/rsa/test/sample_key.pub", jwa.RS256, false},
+		{"../jwa/ecdsa/test/ec256-private.pem", jwa.ES256, true},
+		{"../jwa/ecdsa/test/ec384-public.pem", jwa.ES384, false},
+	}
+
+	for _, tc := range testCases {
+		data, err := ioutil.ReadFile(tc.file)
+		if err != nil {
+			t.Fatalf("Error reading '%s': %v", tc.file, err)
+		}
+
+		key, err := FromPEM(data, tc.alg)
+		if err != nil {
+			t.Errorf("Error parsing '%s': %v", tc.file, err)
+			continue
+		}
+		if key.Algorithm != tc.alg || len(key.ID) == 0 {
+			t.Errorf("Unexpected key parsed from '%s': %#v", tc.file, key)
+		}
+		if key.hasPrivateFields() != tc.private {
+			t.Errorf("Unexpected private fields parsed from '%s'", tc.file)
+		}
+	}
+}
+
+func TestPEMRoundTrip(t *testing.T) {
+	for _, alg := range []string{jwa.RS256, jwa.ES256, jwa.EdDSA} {
+		key, err := GenerateKey(alg, 2048, 1)
+		if err != nil {
+			t.Fatalf("Error generating %s key: %v", alg, err)
+		}
+
+		privPEM, err := key.ToPEM()
+		if err != nil {
+			t.Fatalf("Error encoding %s private key: %v", alg, err)
+		}
+		pubPEM, err := key.ToPublicPEM()
+		if err != nil {
+			t.Fatalf("Error encoding %s public key: %v", alg, err)
+		}
+
+		priv, err := FromPEM(privPEM, "")
+		if err != nil {
+			t.Fatalf("Error decoding %s private key: %v", alg, err)
+		}
+		pub, err := FromPEM(pubPEM, "")
+		if err != nil {
+			t.Fatalf("Error decoding %s public key: %v", alg, err)
+		}
+
+		if priv.D != key.D || priv.X != key.X || priv.N != key.N {
+			t.Errorf("Unexpected %s private key: %#v", alg, priv)
+		}
+		if pub.hasPrivateFields() || pub.X != key.X || pub.N != key.N {
+			t.Errorf("Unexpected %s public key: %#v", alg, pub)
+		}
+		if len(priv.ID) > 0 || len(priv.Algorithm) > 0 {
+			t.Errorf("Identifier and algorithm should not be defined")
+		}
+	}
+}
+
+func TestFromPEMCertificate(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key, err := FromPEM(data, jwa.ES256)
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %v", err)
+	}
+
+	expected, _ := NewKey(&priv.PublicKey)
+	if key.X != expected.X || key.Y != expected.Y || len(key.D) > 0 {
+		t.Errorf("Unexpected key parsed from certificate: %#v", key)
+	}
+}
+
+func TestPEMErrors(t *testing.T) {
+	if _, err := FromPEM([]byte("not a PEM"), ""); err == nil {
+		t.Error("Invalid PEM data should be rejected")
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "DH PARAMETERS"})
+	if _, err := FromPEM(data, ""); err == nil {
+		t.Error("PEM data without keys should be rejected")
+	}
+
+	key, err := GenerateKey(jwa.HS256, 256, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	if _, err := key.ToPEM(); err == nil {
+		t.Error("Symmetric keys should not be encoded as PEM")
+	}
+
+	data, _ = ioutil.ReadFile("../jwa/rsa/test/sample_key")
+	if _, err := FromPEM(data, jwa.ES256); err == nil {
+		t.Error("Incompatible algorithm should be rejected")
+	}
+}
@@ -27,23 +27,35 @@ import (
 // A SetClient represents a client for a service that provides the key set used
 // for signing or encrypting session tokens.
 type SetClient struct {
-	url string
+	url    string
+	client *http.Client
 }
 
 // NewSetClient creates a new instace of a client for key set service.
 func NewSetClient(url string) *SetClient {
 	return &SetClient{
 		url,
+		http.DefaultClient,
 	}
 }
 
-// GetCerts returns the key set from the service.
+// NewSetClientWithHTTP creates a new instace of a client for key set service
+// which uses specified HTTP client to send requests.
+func NewSetClientWithHTTP(url string, client *http.Client) *SetClient {
+	return &SetClient{
+		url,
+		client,
+	}
+}
+
+// GetCerts returns the key set from the service. Invalid keys are skipped, so
+// that a single invalid key does not make the whole key set unusable.
 func (c *SetClient) GetCerts(tracer tlog.Tracer) (*jwk.Set, error) {
 	if tracer == nil {
 		tracer = tlog.NewTracerNop()
 	}
 
-	resp, err := http.Get(c.url)
+	resp, err := c.client.Get(c.url)
 	if err != nil {
 		tracer.AddEntry(
 			tlog.LevelError, "http_error", "HTTP protocol error",
@@ -81,6 +93,18 @@ func (c *SetClient) GetCerts(tracer tlog.Tracer) (*jwk.Set, error) {
 		return nil, err
 	}
 
+	valid := keyset.Keys[:0]
+	for _, k := range keyset.Keys {
+		if err := k.Validate(); err != nil {
+			tracer.AddEntry(
+				tlog.LevelWarn, "invalid_key", "Invalid key skipped: "+k.ID,
+				0, err, "SetClient", "GetCerts", "Validate")
+			continue
+		}
+		valid = append(valid, k)
+	}
+	keyset.Keys = valid
+
 	return &keyset, nil
 }
 
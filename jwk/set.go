@@ -0,0 +1,231 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto"
+	_ "crypto/sha256" // Hash function used by IdentityThumbprint
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/raiqub/jose/converters"
+)
+
+type (
+	// A KeyFilter represents a function which reports whether specified key
+	// should be kept by Set.Filter.
+	KeyFilter func(k *Key) bool
+
+	// A KeyIdentity represents a function which returns the identity of
+	// specified key, used by Set.Merge and Set.Dedup to detect duplicated
+	// keys. Keys having empty identity are never considered duplicated.
+	KeyIdentity func(k *Key) (string, error)
+)
+
+// FilterAlgorithm returns a filter which keeps keys of specified algorithm.
+func FilterAlgorithm(alg string) KeyFilter {
+	return func(k *Key) bool {
+		return k.Algorithm == alg
+	}
+}
+
+// FilterType returns a filter which keeps keys of specified type.
+func FilterType(kty string) KeyFilter {
+	return func(k *Key) bool {
+		return k.Type == kty
+	}
+}
+
+// FilterUsage returns a filter which keeps keys intended for specified use.
+// Keys not declaring their intended use are kept.
+func FilterUsage(use string) KeyFilter {
+	return func(k *Key) bool {
+		return len(k.Usage) == 0 || k.Usage == use
+	}
+}
+
+// FilterValidAt returns a filter which keeps keys valid at specified instant
+// in time.
+func FilterValidAt(t time.Time) KeyFilter {
+	return func(k *Key) bool {
+		return k.IsValidAt(t)
+	}
+}
+
+// IdentityKeyID returns the identifier of specified key as its identity.
+func IdentityKeyID(k *Key) (string, error) {
+	return k.ID, nil
+}
+
+// IdentityThumbprint returns the base64url-encoded SHA-256 thumbprint of
+// specified key as its identity, therefore a private key and its public key
+// are the same key.
+func IdentityThumbprint(k *Key) (string, error) {
+	thumbprint, err := k.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return converters.Base64.FromBytes(thumbprint), nil
+}
+
+// LookupKeyID returns the key of current set having specified identifier.
+func (s *Set) LookupKeyID(kid string) (*Key, bool) {
+	for i := range s.Keys {
+		if s.Keys[i].ID == kid {
+			return &s.Keys[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Filter returns a new set having the keys of current set which are kept by
+// every specified filter.
+func (s *Set) Filter(filters ...KeyFilter) *Set {
+	result := &Set{Keys: make([]Key, 0, len(s.Keys))}
+	for i := range s.Keys {
+		keep := true
+		for _, f := range filters {
+			if keep = f(&s.Keys[i]); !keep {
+				break
+			}
+		}
+
+		if keep {
+			result.Keys = append(result.Keys, s.Keys[i])
+		}
+	}
+
+	return result
+}
+
+// Public returns a new set having the public information of the keys of
+// current set. Symmetric keys are not included since they have no public
+// information.
+func (s *Set) Public() *Set {
+	result := &Set{Keys: make([]Key, 0, len(s.Keys))}
+	for _, k := range s.Keys {
+		if k.IsSymmetric() {
+			continue
+		}
+
+		k.KeyOps = append([]string(nil), k.KeyOps...)
+		k.RemovePrivateFields()
+		result.Keys = append(result.Keys, k)
+	}
+
+	return result
+}
+
+// Merge appends the keys of specified set to current set, skipping the keys
+// whose identity is already found on current set. Current set is not modified
+// when an error is returned.
+func (s *Set) Merge(other *Set, identity KeyIdentity) error {
+	merged := make([]Key, 0, len(s.Keys)+len(other.Keys))
+	merged = append(merged, s.Keys...)
+	merged = append(merged, other.Keys...)
+
+	keys, err := dedupKeys(merged, identity)
+	if err != nil {
+		return err
+	}
+
+	s.Keys = keys
+	return nil
+}
+
+// Dedup removes the keys of current set whose identity matches a previous
+// key, keeping the first occurrence of each key. Current set is not modified
+// when an error is returned.
+func (s *Set) Dedup(identity KeyIdentity) error {
+	keys, err := dedupKeys(s.Keys, identity)
+	if err != nil {
+		return err
+	}
+
+	s.Keys = keys
+	return nil
+}
+
+// dedupKeys returns a new slice having specified keys except the ones whose
+// identity matches a previous key.
+func dedupKeys(keys []Key, identity KeyIdentity) ([]Key, error) {
+	seen := make(map[string]bool, len(keys))
+	result := make([]Key, 0, len(keys))
+	for i := range keys {
+		id, err := identity(&keys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(id) > 0 {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		result = append(result, keys[i])
+	}
+
+	return result, nil
+}
+
+// SortByNotBefore sorts the keys of current set from oldest to newest
+// NotBefore. Keys not defining NotBefore are placed first and the order of
+// keys having same NotBefore is preserved.
+func (s *Set) SortByNotBefore() {
+	sort.SliceStable(s.Keys, func(i, j int) bool {
+		return s.Keys[i].NotBefore.Before(s.Keys[j].NotBefore)
+	})
+}
+
+// UnmarshalJSON parses specified JSON representation of a key set to current
+// instance. Keys of unknown type are skipped, as required by RFC 7517.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	s.Keys = nil
+	for _, raw := range v.Keys {
+		var header struct {
+			Type string `json:"kty"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return err
+		}
+
+		switch header.Type {
+		case KeyTypeECDSA, KeyTypeRSA, KeyTypeSymmetric, KeyTypeOKP:
+		default:
+			continue
+		}
+
+		var k Key
+		if err := json.Unmarshal(raw, &k); err != nil {
+			return err
+		}
+		s.Keys = append(s.Keys, k)
+	}
+
+	return nil
+}
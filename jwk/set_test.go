@@ -0,0 +1,180 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+func testGenerateSet(t *testing.T) *Set {
+	algs := []string{jwa.ES256, jwa.HS256, jwa.RSAOAEP, jwa.EdDSA}
+	set := &Set{}
+	for i, alg := range algs {
+		key, err := GenerateKey(alg, 2048, 1)
+		if err != nil {
+			t.Fatalf("Error generating %s key: %v", alg, err)
+		}
+		key.NotBefore = time.Unix(int64(1000*(len(algs)-i)), 0)
+		set.Keys = append(set.Keys, *key)
+	}
+
+	return set
+}
+
+func TestSetLookupKeyID(t *testing.T) {
+	set := testGenerateSet(t)
+
+	key, ok := set.LookupKeyID(set.Keys[2].ID)
+	if !ok || key != &set.Keys[2] {
+		t.Errorf("Unexpected key found: %v", key)
+	}
+	if _, ok := set.LookupKeyID("unknown"); ok {
+		t.Error("Unknown key identifier should not be found")
+	}
+}
+
+func TestSetFilter(t *testing.T) {
+	set := testGenerateSet(t)
+
+	testCases := []struct {
+		filters  []KeyFilter
+		expected int
+	}{
+		{nil, 4},
+		{[]KeyFilter{FilterType(KeyTypeECDSA)}, 1},
+		{[]KeyFilter{FilterAlgorithm(jwa.RSAOAEP)}, 1},
+		{[]KeyFilter{FilterUsage(UsageSignature)}, 3},
+		{[]KeyFilter{FilterUsage(UsageEncryption)}, 1},
+		{[]KeyFilter{
+			FilterUsage(UsageSignature), FilterType(KeyTypeSymmetric)}, 1},
+		{[]KeyFilter{FilterValidAt(time.Now())}, 4},
+		{[]KeyFilter{FilterValidAt(time.Unix(2500, 0))}, 2},
+	}
+
+	for i, tc := range testCases {
+		result := set.Filter(tc.filters...)
+		if len(result.Keys) != tc.expected {
+			t.Errorf("Case %d: unexpected keys count: %d", i, len(result.Keys))
+		}
+	}
+}
+
+func TestSetPublic(t *testing.T) {
+	set := testGenerateSet(t)
+
+	pub := set.Public()
+	if len(pub.Keys) != 3 {
+		t.Fatalf("Unexpected keys count: %d", len(pub.Keys))
+	}
+	for _, k := range pub.Keys {
+		if k.IsSymmetric() || k.hasPrivateFields() {
+			t.Errorf("Private information found on key '%s'", k.ID)
+		}
+	}
+
+	if !set.Keys[0].hasPrivateFields() {
+		t.Error("Original set should not be changed")
+	}
+}
+
+func TestSetMergeAndDedup(t *testing.T) {
+	set := testGenerateSet(t)
+	other := testGenerateSet(t)
+
+	pub := set.Public()
+	pub.Keys[0].ID = "renamed"
+	if err := other.Merge(pub, IdentityKeyID); err != nil {
+		t.Fatalf("Error merging sets: %v", err)
+	}
+	if len(other.Keys) != 7 {
+		t.Errorf("Unexpected keys count merged by kid: %d", len(other.Keys))
+	}
+
+	if err := other.Merge(set, IdentityThumbprint); err != nil {
+		t.Fatalf("Error merging sets: %v", err)
+	}
+	if len(other.Keys) != 8 {
+		t.Errorf("Unexpected keys count merged by thumbprint: %d",
+			len(other.Keys))
+	}
+
+	count := len(other.Keys)
+	invalid := &Set{Keys: []Key{{Type: "unknown"}}}
+	if err := other.Merge(invalid, IdentityThumbprint); err == nil {
+		t.Error("Merging a key without thumbprint should fail")
+	}
+	if len(other.Keys) != count {
+		t.Errorf("A failed merge should not modify the set: %d",
+			len(other.Keys))
+	}
+
+	id, err := IdentityThumbprint(&set.Keys[0])
+	if err != nil {
+		t.Fatalf("Error computing identity: %v", err)
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(id); err != nil {
+		t.Errorf("The identity should be base64url-encoded: %q", id)
+	}
+
+	dup := &Set{Keys: append(set.Keys, set.Keys...)}
+	if err := dup.Dedup(IdentityKeyID); err != nil {
+		t.Fatalf("Error removing duplicated keys: %v", err)
+	}
+	if len(dup.Keys) != len(set.Keys) {
+		t.Errorf("Unexpected keys count: %d", len(dup.Keys))
+	}
+}
+
+func TestSetSortByNotBefore(t *testing.T) {
+	set := testGenerateSet(t)
+	set.Keys[1].NotBefore = time.Time{}
+
+	set.SortByNotBefore()
+	for i := 1; i < len(set.Keys); i++ {
+		if set.Keys[i].NotBefore.Before(set.Keys[i-1].NotBefore) {
+			t.Fatalf("Keys are not sorted: %v", set.Keys)
+		}
+	}
+	if !set.Keys[0].NotBefore.IsZero() {
+		t.Error("Keys not defining NotBefore should be placed first")
+	}
+}
+
+func TestSetUnmarshalUnknownType(t *testing.T) {
+	input := `{"keys": [
+		{"kty": "PQC", "alg": "ML-DSA-44", "pub": "AAAA"},
+		{"kty": "oct", "kid": "hmac", "k": "AyM32w"},
+		{"kid": "untyped"}
+	]}`
+
+	var set Set
+	if err := json.Unmarshal([]byte(input), &set); err != nil {
+		t.Fatalf("Error decoding key set: %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].ID != "hmac" {
+		t.Errorf("Unexpected keys decoded: %v", set.Keys)
+	}
+
+	if err := json.Unmarshal([]byte(`{"keys": [1]}`), &set); err == nil {
+		t.Error("Malformed keys should be rejected")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/raiqub/jose/converters"
+)
+
+const (
+	// ThumbprintURIPrefix defines the prefix of JWK thumbprint URIs as defined
+	// by RFC 9278.
+	ThumbprintURIPrefix = "urn:ietf:params:oauth:jwk-thumbprint:"
+)
+
+// Names of hash functions as defined by Named Information Hash Algorithm
+// Registry, which are used by JWK thumbprint URIs.
+var thumbprintHashNames = map[crypto.Hash]string{
+	crypto.SHA256:   "sha-256",
+	crypto.SHA384:   "sha-384",
+	crypto.SHA512:   "sha-512",
+	crypto.SHA3_256: "sha3-256",
+	crypto.SHA3_384: "sha3-384",
+	crypto.SHA3_512: "sha3-512",
+}
+
+// Thumbprint computes the JWK thumbprint of current key using specified hash
+// function, as defined by RFC 7638. Only the required members of current key
+// type are hashed, therefore both private and public keys have the same
+// thumbprint.
+func (k *Key) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, ErrUnavailableHash(hash)
+	}
+
+	var members map[string]string
+	switch k.Type {
+	case KeyTypeECDSA:
+		members = map[string]string{
+			"crv": k.Curve, "kty": k.Type, "x": k.X, "y": k.Y,
+		}
+	case KeyTypeRSA:
+		members = map[string]string{
+			"e": k.E, "kty": k.Type, "n": k.N,
+		}
+	case KeyTypeSymmetric:
+		members = map[string]string{
+			"k": k.K, "kty": k.Type,
+		}
+	case KeyTypeOKP:
+		members = map[string]string{
+			"crv": k.Curve, "kty": k.Type, "x": k.X,
+		}
+	default:
+		return nil, ErrUnknownType(k.Type)
+	}
+
+	for name, value := range members {
+		if len(value) == 0 {
+			return nil, ErrInvalidKeyData("missing required member " + name)
+		}
+	}
+
+	// Map keys are sorted lexicographically and no whitespace is added
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// ThumbprintURI returns the JWK thumbprint URI of current key using specified
+// hash function, as defined by RFC 9278.
+func (k *Key) ThumbprintURI(hash crypto.Hash) (string, error) {
+	name, ok := thumbprintHashNames[hash]
+	if !ok {
+		return "", ErrUnavailableHash(hash)
+	}
+
+	sum, err := k.Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+
+	return ThumbprintURIPrefix + name + ":" + converters.Base64.FromBytes(sum),
+		nil
+}
+
+// ThumbprintURIs returns the JWK thumbprint URIs of current key using every
+// available hash function, sorted lexicographically.
+func (k *Key) ThumbprintURIs() ([]string, error) {
+	uris := make([]string, 0, len(thumbprintHashNames))
+	for hash := range thumbprintHashNames {
+		if !hash.Available() {
+			continue
+		}
+
+		uri, err := k.ThumbprintURI(hash)
+		if err != nil {
+			return nil, err
+		}
+		uris = append(uris, uri)
+	}
+
+	sort.Strings(uris)
+	return uris, nil
+}
+
+// MatchThumbprintURI returns whether specified JWK thumbprint URI identifies
+// current key.
+func (k *Key) MatchThumbprintURI(uri string) (bool, error) {
+	hash, sum, err := ParseThumbprintURI(uri)
+	if err != nil {
+		return false, err
+	}
+
+	actual, err := k.Thumbprint(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(actual, sum), nil
+}
+
+// ParseThumbprintURI parses specified JWK thumbprint URI and returns its hash
+// function and thumbprint.
+func ParseThumbprintURI(uri string) (crypto.Hash, []byte, error) {
+	if !strings.HasPrefix(uri, ThumbprintURIPrefix) {
+		return 0, nil, ErrInvalidThumbprintURI(uri)
+	}
+
+	parts := strings.Split(uri[len(ThumbprintURIPrefix):], ":")
+	if len(parts) != 2 {
+		return 0, nil, ErrInvalidThumbprintURI(uri)
+	}
+
+	var hash crypto.Hash
+	for h, name := range thumbprintHashNames {
+		if name == parts[0] {
+			hash = h
+		}
+	}
+	if hash == 0 {
+		return 0, nil, ErrInvalidThumbprintURI(uri)
+	}
+
+	sum, err := converters.Base64.ToBytes(parts[1])
+	if err != nil || len(sum) != hash.Size() {
+		return 0, nil, ErrInvalidThumbprintURI(uri)
+	}
+
+	return hash, sum, nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"testing"
+
+	"github.com/raiqub/jose/converters"
+	"github.com/raiqub/jose/jwa"
+)
+
+func TestThumbprintRFC7638(t *testing.T) {
+	// Example from RFC 7638 section 3.1
+	key := Key{
+		Type: KeyTypeRSA,
+		ID:   "2011-04-29",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zw" +
+			"u1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4" +
+			"Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSq" +
+			"zs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI" +
+			"4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8" +
+			"awapJzKnqDKgw",
+		E:         "AQAB",
+		Algorithm: "RS256",
+	}
+
+	sum, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Error computing thumbprint: %v", err)
+	}
+
+	expected := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if result := converters.Base64.FromBytes(sum); result != expected {
+		t.Errorf("Unexpected thumbprint: %s", result)
+	}
+
+	key.N = ""
+	if _, err := key.Thumbprint(crypto.SHA256); err == nil {
+		t.Error("Thumbprint of key missing required members should fail")
+	}
+}
+
+func TestThumbprintKeyTypes(t *testing.T) {
+	testCases := []struct {
+		key      Key
+		expected string
+	}{
+		{Key{
+			Type:  KeyTypeECDSA,
+			Curve: "P-256",
+			X:     "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+			Y:     "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+			D:     "jpsQnnGQmL-YBIffH1136cspYG6-0iY7X1fCE9-E9LI",
+		}, "oKIywvGUpTVTyxMQ3bwIIeQUudfr_CkLMjCE19ECD-U"},
+		{Key{
+			Type: KeyTypeSymmetric,
+			K:    "GawgguFyGrWKav7AX4VKUg",
+		}, "k1JnWRfC-5zzmL72vXIuBgTLfVROXBakS4OmGcrMCoc"},
+		// Example from RFC 8037 appendix A.3
+		{Key{
+			Type:  KeyTypeOKP,
+			Curve: "Ed25519",
+			X:     "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+		}, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"},
+	}
+
+	for _, tc := range testCases {
+		id, err := ThumbprintID(&tc.key, crypto.SHA256)
+		if err != nil {
+			t.Fatalf("Error computing %s thumbprint: %v", tc.key.Type, err)
+		}
+		if id != tc.expected {
+			t.Errorf("Unexpected %s thumbprint: %s", tc.key.Type, id)
+		}
+	}
+}
+
+func TestThumbprintIDGenerator(t *testing.T) {
+	SetThumbprintIDGenerator(crypto.SHA256)
+	defer SetIDGenerator(DefaultKeyIDGenerator)
+
+	generated, err := GenerateKey(jwa.EdDSA, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	key, _ := generated.Key()
+
+	var first, second Key
+	if err := first.SetKey(key, jwa.EdDSA); err != nil {
+		t.Fatalf("Error setting key: %v", err)
+	}
+	if err := second.SetKey(key, jwa.EdDSA); err != nil {
+		t.Fatalf("Error setting key: %v", err)
+	}
+
+	if first.ID != second.ID {
+		t.Errorf("The same key got different identifiers: %s and %s",
+			first.ID, second.ID)
+	}
+	if expected, _ := ThumbprintID(&first, crypto.SHA256); first.ID != expected {
+		t.Errorf("Unexpected key identifier: %s", first.ID)
+	}
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	var sym Key
+	if err := sym.SetKey(secret, jwa.HS256); err != nil {
+		t.Fatalf("Error setting key: %v", err)
+	}
+	thumbprint, _ := ThumbprintID(&sym, crypto.SHA256)
+	if sym.ID == thumbprint {
+		t.Error("Symmetric key identifier should not disclose its thumbprint")
+	}
+}
+
+func TestThumbprintURI(t *testing.T) {
+	// Example from RFC 9278 section 3
+	key := Key{
+		Type: KeyTypeRSA,
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zw" +
+			"u1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4" +
+			"Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSq" +
+			"zs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI" +
+			"4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8" +
+			"awapJzKnqDKgw",
+		E: "AQAB",
+	}
+	expected := "urn:ietf:params:oauth:jwk-thumbprint:sha-256:" +
+		"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+	uri, err := key.ThumbprintURI(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Error computing thumbprint URI: %v", err)
+	}
+	if uri != expected {
+		t.Errorf("Unexpected thumbprint URI: %s", uri)
+	}
+
+	if ok, err := key.MatchThumbprintURI(expected); !ok || err != nil {
+		t.Errorf("The thumbprint URI should match the key: %v", err)
+	}
+
+	uris, err := key.ThumbprintURIs()
+	if err != nil {
+		t.Fatalf("Error computing thumbprint URIs: %v", err)
+	}
+	found := false
+	for _, u := range uris {
+		found = found || u == expected
+	}
+	if !found {
+		t.Errorf("The thumbprint URIs should include %s: %v", expected, uris)
+	}
+
+	for _, invalid := range []string{
+		"urn:ietf:params:oauth:jwk-thumbprint:sha-256",
+		"urn:ietf:params:oauth:jwk-thumbprint:md5:NzbLsXh8uDCcd-6MNwXF4W",
+		"urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W",
+		"urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbL:sXh8",
+		"urn:example:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+	} {
+		if _, _, err := ParseThumbprintURI(invalid); err == nil {
+			t.Errorf("The thumbprint URI should be invalid: %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/raiqub/jose/converters"
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwa/secp256k1"
+)
+
+const (
+	// MinimumRSAKeySize defines the minimum size in bits for RSA keys as
+	// required by JWA specification.
+	MinimumRSAKeySize = 2048
+)
+
+var (
+	// Curves required by ECDSA algorithms.
+	// Ref: https://tools.ietf.org/html/rfc7518#section-3.4.
+	ecdsaCurves = map[string]string{
+		jwa.ES256:  "P-256",
+		jwa.ES384:  "P-384",
+		jwa.ES512:  "P-521",
+		jwa.ES256K: "secp256k1",
+	}
+
+	// Minimum sizes in bits of symmetric keys, which must be at least the size
+	// of hash output as defined by RFC 7518 section 3.2.
+	symmetricMinimumSizes = map[string]int{
+		jwa.HS256: 256,
+		jwa.HS384: 384,
+		jwa.HS512: 512,
+	}
+
+	// Required sizes in bits of symmetric keys.
+	symmetricSizes = map[string]int{
+		jwa.A128KW: 128,
+		jwa.A192KW: 192,
+		jwa.A256KW: 256,
+	}
+)
+
+// Validate checks whether the key material of current key is consistent with
+// its type, curve and algorithm. Public keys must lie on their curve, private
+// keys must match their public keys, key sizes must be suitable for declared
+// algorithm and "key_ops" must be consistent with "use".
+func (k *Key) Validate() error {
+	if err := k.validateOps(); err != nil {
+		return err
+	}
+
+	switch k.Type {
+	case KeyTypeECDSA:
+		return k.validateECDSA()
+	case KeyTypeRSA:
+		return k.validateRSA()
+	case KeyTypeSymmetric:
+		return k.validateSymmetric()
+	case KeyTypeOKP:
+		return k.validateOKP()
+	default:
+		return ErrUnknownType(k.Type)
+	}
+}
+
+func (k *Key) validateECDSA() error {
+	if expected, ok := ecdsaCurves[k.Algorithm]; ok && k.Curve != expected {
+		return ErrInvalidKeyData(fmt.Sprintf(
+			"curve %s cannot be used with %s", k.Curve, k.Algorithm))
+	}
+
+	curve, err := curveFromName(k.Curve)
+	if err != nil {
+		return err
+	}
+	size := curveSize(curve)
+
+	x, err := converters.Base64.ToBytes(k.X)
+	if err != nil || len(x) != size {
+		return ErrInvalidKeyData("invalid EC x coordinate length")
+	}
+	y, err := converters.Base64.ToBytes(k.Y)
+	if err != nil || len(y) != size {
+		return ErrInvalidKeyData("invalid EC y coordinate length")
+	}
+	if len(k.D) > 0 {
+		d, err := converters.Base64.ToBytes(k.D)
+		if err != nil || len(d) > size {
+			return ErrInvalidKeyData("invalid EC private key length")
+		}
+	}
+
+	raw, err := k.getECDSA()
+	if err != nil {
+		return ErrInvalidKeyData(err.Error())
+	}
+
+	if curve == secp256k1.S256() {
+		// Not supported by crypto/ecdh
+		switch key := raw.(type) {
+		case *ecdsa.PrivateKey:
+			if err := secp256k1.ValidatePrivateKey(key); err != nil {
+				return ErrInvalidKeyData(
+					"EC private key does not match public key")
+			}
+		case *ecdsa.PublicKey:
+			if !curve.IsOnCurve(key.X, key.Y) {
+				return ErrInvalidKeyData("EC point is not on curve")
+			}
+		}
+		return nil
+	}
+
+	var pub *ecdsa.PublicKey
+	switch key := raw.(type) {
+	case *ecdsa.PrivateKey:
+		pub = &key.PublicKey
+	case *ecdsa.PublicKey:
+		pub = key
+	}
+
+	ecdhPub, err := pub.ECDH()
+	if err != nil {
+		return ErrInvalidKeyData("EC point is not on curve")
+	}
+
+	priv, ok := raw.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil
+	}
+
+	ecdhPriv, err := priv.ECDH()
+	if err != nil {
+		return ErrInvalidKeyData("EC private key is out of range")
+	}
+	if !ecdhPriv.PublicKey().Equal(ecdhPub) {
+		return ErrInvalidKeyData("EC private key does not match public key")
+	}
+
+	return nil
+}
+
+func (k *Key) validateRSA() error {
+	if len(k.D) > 0 && (len(k.PrimeP) == 0 || len(k.PrimeQ) == 0) {
+		return ErrInvalidKeyData("incomplete RSA private key")
+	}
+
+	raw, err := k.getRSA()
+	if err != nil {
+		return ErrInvalidKeyData(err.Error())
+	}
+
+	var pub *rsa.PublicKey
+	switch key := raw.(type) {
+	case *rsa.PrivateKey:
+		if err := key.Validate(); err != nil {
+			return ErrInvalidKeyData(err.Error())
+		}
+		pub = &key.PublicKey
+	case *rsa.PublicKey:
+		pub = key
+	}
+
+	if pub.E < 3 || pub.E%2 == 0 {
+		return ErrInvalidKeyData("invalid RSA public exponent")
+	}
+	if pub.N.BitLen() < MinimumRSAKeySize {
+		return jwa.ErrTooSmallKeySize{
+			Minimum: MinimumRSAKeySize,
+			Actual:  pub.N.BitLen(),
+		}
+	}
+
+	return nil
+}
+
+func (k *Key) validateSymmetric() error {
+	key, err := k.getSymmetric()
+	if err != nil || len(key) == 0 {
+		return ErrInvalidKeyData("invalid symmetric key")
+	}
+
+	bits := len(key) * 8
+	if minimum, ok := symmetricMinimumSizes[k.Algorithm]; ok &&
+		bits < minimum {
+		return jwa.ErrTooSmallKeySize{
+			Minimum: minimum,
+			Actual:  bits,
+		}
+	}
+	if expected, ok := symmetricSizes[k.Algorithm]; ok && bits != expected {
+		return jwa.ErrInvalidKeySize{
+			Expected: expected,
+			Actual:   bits,
+		}
+	}
+
+	return nil
+}
+
+func (k *Key) validateOKP() error {
+	raw, err := k.getOKP()
+	if err != nil {
+		return err
+	}
+
+	x, _ := converters.Base64.ToBytes(k.X)
+	var pub []byte
+	switch key := raw.(type) {
+	case ed25519.PrivateKey:
+		pub = key.Public().(ed25519.PublicKey)
+	case *ecdh.PrivateKey:
+		pub = key.PublicKey().Bytes()
+	default:
+		return nil
+	}
+
+	if !bytes.Equal(pub, x) {
+		return ErrInvalidKeyData("OKP private key does not match public key")
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwk
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		modify func(*Key)
+		valid  bool
+	}{
+		{"EC public", ecdsaPublicKey, func(*Key) {}, true},
+		{"EC private", ecdsaPrivateKey, func(*Key) {}, true},
+		{"EC off curve", ecdsaPublicKey, func(k *Key) {
+			k.X, k.Y = k.Y, k.X
+		}, false},
+		{"EC short coordinate", ecdsaPublicKey, func(k *Key) {
+			k.X = k.X[2:]
+		}, false},
+		{"EC ES512", ecdsaPublicKey, func(k *Key) {
+			k.Algorithm = jwa.ES512
+		}, true},
+		{"EC ES256 with P-521", ecdsaPublicKey, func(k *Key) {
+			k.Algorithm = jwa.ES256
+		}, false},
+		{"EC ES384 with P-521", ecdsaPrivateKey, func(k *Key) {
+			k.Algorithm = jwa.ES384
+		}, false},
+		{"EC wrong private", ecdsaPrivateKey, func(k *Key) {
+			k.D = "AAhRON2r9cqXX1hg-RoI6R1tX5p2rUAYdmpHZoC1XNM56KtscrX6zbKipQ" +
+				"rCW9CGZH3T4ubpnoTKLDYJ_fF3_rJu"
+		}, false},
+		{"RSA public", rsaPublicKey, func(*Key) {}, true},
+		{"RSA private", rsaPrivateKey, func(*Key) {}, true},
+		{"RSA even exponent", rsaPublicKey, func(k *Key) {
+			k.E = "AQAC"
+		}, false},
+		{"RSA inconsistent", rsaPrivateKey, func(k *Key) {
+			k.PrimeP, k.PrimeQ = k.PrimeQ, k.PrimeP+"AA"
+		}, false},
+		{"RSA incomplete", rsaPrivateKey, func(k *Key) {
+			k.PrimeQ = ""
+		}, false},
+		{"HS256", symKey, func(*Key) {}, true},
+		{"HS512 short", symKey, func(k *Key) {
+			k.Algorithm = jwa.HS512
+		}, false},
+		{"A128KW", symKey, func(k *Key) {
+			k.Algorithm = jwa.A128KW
+		}, false},
+		{"Ed25519", okpEd25519Key, func(*Key) {}, true},
+		{"Ed25519 wrong private", okpEd25519Key, func(k *Key) {
+			k.D = "hJtXIZ2uSN5kbQfbtTNWbpdmhkV8FJG-Onbc6mxCcYg"
+		}, false},
+		{"X25519", okpX25519Key, func(*Key) {}, true},
+		{"X25519 wrong private", okpX25519Key, func(k *Key) {
+			k.D = "hJtXIZ2uSN5kbQfbtTNWbpdmhkV8FJG-Onbc6mxCcYg"
+		}, false},
+		{"unknown", symKey, func(k *Key) {
+			k.Type = "unknown"
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		var key Key
+		input := strings.NewReplacer("\n", "", " ", "").Replace(tc.input)
+		if err := json.Unmarshal([]byte(input), &key); err != nil {
+			t.Fatalf("Error decoding %s key: %v", tc.name, err)
+		}
+		tc.modify(&key)
+
+		if err := key.Validate(); (err == nil) != tc.valid {
+			t.Errorf("Unexpected validation result for %s key: %v",
+				tc.name, err)
+		}
+	}
+}
+
+func TestValidateRSASize(t *testing.T) {
+	raw, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	var key Key
+	err = key.SetKey(raw, jwa.RS256)
+	if _, ok := err.(jwa.ErrTooSmallKeySize); !ok {
+		t.Errorf("Unexpected error for small RSA key: %v", err)
+	}
+
+	if err := key.SetKey([]byte("too short"), jwa.HS256); err == nil {
+		t.Error("A short HMAC key should be rejected")
+	}
+}
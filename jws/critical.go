@@ -0,0 +1,130 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"encoding/json"
+)
+
+const (
+	// HeaderCritical defines the name of header parameter which lists the
+	// extensions that must be understood and processed.
+	HeaderCritical = "crit"
+)
+
+// A CriticalHandler defines a function to process an extension header
+// parameter listed as critical. It receives the decoded header along with the
+// raw JSON value of the parameter, and returns an error when the token must be
+// rejected.
+type CriticalHandler func(header Header, value json.RawMessage) error
+
+// A CriticalParams represents the extension header parameters listed as
+// critical by a header, which were checked by CheckCritical. Their handlers
+// are called by Process only after the token is verified.
+type CriticalParams []criticalParam
+
+// A criticalParam represents a critical extension header parameter along with
+// its handler.
+type criticalParam struct {
+	handler CriticalHandler
+	value   json.RawMessage
+}
+
+var (
+	criticalHandlers = map[string]CriticalHandler{
+		// Processed when encoding and decoding tokens.
+		HeaderBase64: nil,
+	}
+)
+
+// RegisterCritical registers specified extension header parameter as
+// understood, so that tokens listing it as critical are accepted. The handler
+// is called for every verified token listing the parameter as critical, and
+// might be nil when no additional processing is required. This is intended to
+// be called from the init function of applications and packages that
+// implement header extensions.
+func RegisterCritical(name string, handler CriticalHandler) {
+	criticalHandlers[name] = handler
+}
+
+// CheckCritical checks the critical parameters of specified JSON
+// representation of a header as defined by RFC 7515 section 4.1.11. Every
+// parameter listed as critical must be present on header, must not be one of
+// registered header parameters and must be understood, which is defined by
+// having a handler. No handler is called, since the token is not verified
+// yet.
+func CheckCritical(
+	data []byte,
+	registered map[string]bool,
+	understood map[string]CriticalHandler,
+) (CriticalParams, error) {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, err
+	}
+
+	rawCrit, ok := params[HeaderCritical]
+	if !ok {
+		return nil, nil
+	}
+
+	var crit []string
+	if err := json.Unmarshal(rawCrit, &crit); err != nil || len(crit) == 0 {
+		return nil, ErrCritical(HeaderCritical)
+	}
+
+	result := make(CriticalParams, 0, len(crit))
+	for _, name := range crit {
+		if registered[name] {
+			return nil, ErrCritical(name)
+		}
+
+		handler, ok := understood[name]
+		if !ok {
+			return nil, ErrCritical(name)
+		}
+
+		value, ok := params[name]
+		if !ok {
+			return nil, ErrCritical(name)
+		}
+
+		if handler != nil {
+			result = append(result, criticalParam{handler, value})
+		}
+	}
+
+	return result, nil
+}
+
+// Process calls the handlers of current critical parameters, which must only
+// be called after the token is verified.
+func (c CriticalParams) Process(header Header) error {
+	for _, p := range c {
+		if err := p.handler(header, p.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCritical checks the critical parameters of specified JWS header, where
+// data is its JSON representation.
+func checkCritical(data []byte) (CriticalParams, error) {
+	return CheckCritical(data, registeredParams, criticalHandlers)
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jws"
+)
+
+var errWrongTenant = errors.New("wrong tenant")
+
+func init() {
+	jws.RegisterCritical("tenant", func(h jws.Header, v json.RawMessage) error {
+		var tenant string
+		if err := json.Unmarshal(v, &tenant); err != nil {
+			return err
+		}
+		if tenant != "acme" {
+			return errWrongTenant
+		}
+		return nil
+	})
+}
+
+func signHeader(t *testing.T, header string, payload []byte) string {
+	method, err := jwa.New(jwa.HS256)
+	if err != nil {
+		t.Fatalf("Error creating algorithm: %v", err)
+	}
+
+	input := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := method.Sign(input, oldKey)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	return input + "." + sig
+}
+
+func TestCritical(t *testing.T) {
+	testCases := []struct {
+		header string
+		err    error
+	}{
+		{`{"alg":"HS256"}`, nil},
+		{`{"alg":"HS256","tenant":"acme","crit":["tenant"]}`, nil},
+		{`{"alg":"HS256","tenant":"other","crit":["tenant"]}`, errWrongTenant},
+		{`{"alg":"HS256","crit":["tenant"]}`, jws.ErrCritical("tenant")},
+		{`{"alg":"HS256","ext":1,"crit":["ext"]}`, jws.ErrCritical("ext")},
+		{`{"alg":"HS256","crit":["alg"]}`, jws.ErrCritical("alg")},
+		{`{"alg":"HS256","crit":[]}`, jws.ErrCritical("crit")},
+	}
+
+	for _, tc := range testCases {
+		token := signHeader(t, tc.header, []byte("payload"))
+		_, err := jws.DecodeAndVerify(token, nil, getOldKey)
+		if err != tc.err {
+			t.Errorf("Unexpected error for header %s: %v", tc.header, err)
+		}
+	}
+}
+
+func TestCriticalJSON(t *testing.T) {
+	payload, err := json.Marshal(newClaims())
+	if err != nil {
+		t.Fatalf("Error encoding claims: %v", err)
+	}
+
+	testCases := []struct {
+		header string
+		valid  bool
+	}{
+		{`{"alg":"HS256","tenant":"acme","crit":["tenant"]}`, true},
+		{`{"alg":"HS256","ext":1,"crit":["ext"]}`, false},
+		{`{"alg":"HS256","tenant":"other","crit":["tenant"]}`, false},
+	}
+
+	for _, tc := range testCases {
+		segs := strings.Split(signHeader(t, tc.header, payload), ".")
+		data := `{"payload":"` + segs[1] + `","protected":"` + segs[0] +
+			`","signature":"` + segs[2] + `"}`
+
+		_, err = jws.DecodeAndValidateJSON([]byte(data), nil, nil, getOldKey)
+		if _, ok := err.(jws.ErrInvalidSignature); ok == tc.valid {
+			t.Errorf("Unexpected error for header %s: %v", tc.header, err)
+		}
+	}
+}
+
+func TestCriticalAfterVerification(t *testing.T) {
+	called := false
+	jws.RegisterCritical("audit", func(h jws.Header, v json.RawMessage) error {
+		called = true
+		return nil
+	})
+
+	token := signHeader(t, `{"alg":"HS256","audit":1,"crit":["audit"]}`,
+		[]byte("payload"))
+	tampered := token[:strings.LastIndex(token, ".")+1] + "AAAA"
+
+	if _, err := jws.DecodeAndVerify(tampered, nil, getOldKey); err == nil {
+		t.Fatal("Tampered token should not be verified")
+	}
+	if called {
+		t.Error("Critical handler should not be called before verification")
+	}
+
+	if _, err := jws.DecodeAndVerify(token, nil, getOldKey); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("Critical handler should be called after verification")
+	}
+}
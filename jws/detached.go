@@ -0,0 +1,135 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/raiqub/jose/jwa"
+)
+
+const (
+	// HeaderBase64 defines the name of header parameter which determines
+	// whether the payload is base64url-encoded (RFC 7797).
+	HeaderBase64 = "b64"
+)
+
+// SignDetached signs specified payload and returns a token whose
+// payload segment is empty, as the payload is expected to be transported
+// separately. When header defines "b64" as false the payload is signed as is,
+// without base64url-encoding, and "b64" is added to critical parameters.
+func SignDetached(
+	header *RegHeader,
+	payload []byte,
+	key interface{},
+) (string, error) {
+	if !header.IsPayloadEncoded() {
+		addBase64Critical(header)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeHeader(&buf, header); err != nil {
+		return "", err
+	}
+	b64header := buf.String()
+
+	method, err := jwa.New(header.GetAlgorithm())
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := method.Sign(signingInput(header, b64header, payload), key)
+	if err != nil {
+		return "", err
+	}
+
+	buf.WriteString("..")
+	buf.WriteString(sig)
+
+	return buf.String(), nil
+}
+
+// VerifyDetached decodes a token whose payload was detached and verifies its
+// signature against specified payload. Returns the decoded header when the
+// signature is valid.
+func VerifyDetached(
+	token string,
+	payload []byte,
+	getKey GetKeyFunc,
+) (*RegHeader, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 || len(segs[1]) != 0 {
+		return nil, ErrInvalidFormat(token)
+	}
+
+	b64in, err := base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, err
+	}
+	header := &RegHeader{}
+	if err := ffjson.Unmarshal(b64in, header); err != nil {
+		return nil, err
+	}
+	if err := checkBase64(header); err != nil {
+		return nil, err
+	}
+	crit, err := checkCritical(b64in)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := jwa.New(header.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	if getKey == nil {
+		return nil, ErrGetKey(token)
+	}
+	key, err := getKey(header)
+	if err != nil {
+		return nil, err
+	}
+
+	input := signingInput(header, segs[0], payload)
+	if err := method.Verify(input, segs[2], key); err != nil {
+		return nil, ErrInvalidSignature(token)
+	}
+	if err := crit.Process(header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// signingInput returns the JWS signing input for specified encoded header and
+// payload content.
+func signingInput(header *RegHeader, b64header string, content []byte) string {
+	var buf bytes.Buffer
+	buf.WriteString(b64header)
+	buf.WriteString(".")
+	if header.IsPayloadEncoded() {
+		buf.WriteString(base64.RawURLEncoding.EncodeToString(content))
+	} else {
+		buf.Write(content)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jws"
+)
+
+// Examples from RFC 7797 section 4.
+const (
+	rfc7797Key = "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0" +
+		"iPS4hcgUuTwjAzZr1Z9CAow"
+	rfc7797Payload  = "$.02"
+	rfc7797Encoded  = "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+	rfc7797Unencode = "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.." +
+		"A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+)
+
+func rfc7797GetKey(t *testing.T) jws.GetKeyFunc {
+	key, err := base64.RawURLEncoding.DecodeString(rfc7797Key)
+	if err != nil {
+		t.Fatalf("Error decoding key: %v", err)
+	}
+
+	return func(jws.Header) (interface{}, error) {
+		return key, nil
+	}
+}
+
+func TestDetachedRFC7797(t *testing.T) {
+	getKey := rfc7797GetKey(t)
+	key, _ := getKey(nil)
+
+	token, err := jws.SignDetached(&jws.RegHeader{Algorithm: jwa.HS256},
+		[]byte(rfc7797Payload), key)
+	if err != nil {
+		t.Fatalf("Error signing detached payload: %v", err)
+	}
+
+	for _, token := range []string{token, rfc7797Encoded, rfc7797Unencode} {
+		_, err := jws.VerifyDetached(token,
+			[]byte(rfc7797Payload), getKey)
+		if err != nil {
+			t.Errorf("Error verifying token %q: %v", token, err)
+		}
+
+		_, err = jws.VerifyDetached(token,
+			[]byte(rfc7797Payload+"0"), getKey)
+		if _, ok := err.(jws.ErrInvalidSignature); !ok {
+			t.Errorf("Unexpected error verifying modified payload: %v", err)
+		}
+	}
+}
+
+func TestDetachedUnencoded(t *testing.T) {
+	payload := []byte("raw webhook body.\nwith dots and binary \x00\xff")
+	b64 := false
+	header := jws.NewHeader(jwa.HS256)
+	header.Base64 = &b64
+
+	token, err := jws.SignDetached(header, payload, oldKey)
+	if err != nil {
+		t.Fatalf("Error signing detached payload: %v", err)
+	}
+	if !header.IsCritical(jws.HeaderBase64) {
+		t.Error("The b64 header parameter should be listed as critical")
+	}
+
+	result, err := jws.VerifyDetached(token, payload,
+		func(jws.Header) (interface{}, error) { return oldKey, nil })
+	if err != nil {
+		t.Fatalf("Error verifying token: %v", err)
+	}
+	if result.IsPayloadEncoded() {
+		t.Error("The payload should be defined as unencoded")
+	}
+}
+
+func TestDetachedInvalid(t *testing.T) {
+	getKey := rfc7797GetKey(t)
+
+	// b64 not listed as critical: {"alg":"HS256","b64":false}
+	token := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2V9..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+	_, err := jws.VerifyDetached(token, []byte(rfc7797Payload), getKey)
+	if _, ok := err.(jws.ErrCritical); !ok {
+		t.Errorf("Unexpected error for non-critical b64: %v", err)
+	}
+
+	// Attached payload
+	token = "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+	_, err = jws.VerifyDetached(token, []byte(rfc7797Payload), getKey)
+	if _, ok := err.(jws.ErrInvalidFormat); !ok {
+		t.Errorf("Unexpected error for attached payload: %v", err)
+	}
+}
@@ -16,6 +16,25 @@
 
 package jws
 
+// An ErrCritical represents an error when a critical header parameter is
+// invalid or is not understood, or when a header parameter is not listed as
+// critical although it is required to.
+type ErrCritical string
+
+// Error returns string representation of current instance error.
+func (e ErrCritical) Error() string {
+	return "Invalid critical header parameter: " + string(e)
+}
+
+// An ErrDuplicateParam represents an error when a header parameter is defined
+// more than once.
+type ErrDuplicateParam string
+
+// Error returns string representation of current instance error.
+func (e ErrDuplicateParam) Error() string {
+	return "The header parameter is defined more than once: " + string(e)
+}
+
 // An ErrGetKey represents an error when was unable to retrieve token signing
 // key.
 type ErrGetKey string
@@ -25,6 +44,17 @@ func (e ErrGetKey) Error() string {
 	return "Error getting the signing key for token"
 }
 
+// An ErrInvalidChain represents an error when the certificate chain of a token
+// could not be validated.
+type ErrInvalidChain struct {
+	Err error
+}
+
+// Error returns string representation of current instance error.
+func (e ErrInvalidChain) Error() string {
+	return "The certificate chain is invalid: " + e.Err.Error()
+}
+
 // An ErrInvalidFormat represents an error when token format is invalid.
 type ErrInvalidFormat string
 
@@ -50,3 +80,39 @@ type ErrInvalidToken string
 func (e ErrInvalidToken) Error() string {
 	return "Error validating JWT token"
 }
+
+// An ErrThumbprintMismatch represents an error when a thumbprint header
+// parameter does not match the key used to sign the token.
+type ErrThumbprintMismatch string
+
+// Error returns string representation of current instance error.
+func (e ErrThumbprintMismatch) Error() string {
+	return "The thumbprint does not match the signing key: " + string(e)
+}
+
+// An ErrUnprotectedParam represents an error when a header parameter which
+// must be integrity protected is not defined by the protected header.
+type ErrUnprotectedParam string
+
+// Error returns string representation of current instance error.
+func (e ErrUnprotectedParam) Error() string {
+	return "The header parameter must be protected: " + string(e)
+}
+
+// An ErrUnsupportedHeader represents an error when token header defines a
+// parameter value which is not supported by current implementation.
+type ErrUnsupportedHeader string
+
+// Error returns string representation of current instance error.
+func (e ErrUnsupportedHeader) Error() string {
+	return "Unsupported header parameter: " + string(e)
+}
+
+// An ErrUntrustedKey represents an error when the key embedded into a token is
+// not trusted.
+type ErrUntrustedKey string
+
+// Error returns string representation of current instance error.
+func (e ErrUntrustedKey) Error() string {
+	return "The embedded key is not trusted"
+}
@@ -25,6 +25,7 @@ type Header interface {
 	GetID() string
 	GetAlgorithm() string
 	GetJWKSetURL() string
+	GetExtra(name string) (interface{}, bool)
 
 	json.Marshaler
 	json.Unmarshaler
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"crypto"
+
+	"github.com/raiqub/jose/jwk"
+)
+
+// A JWKResolver represents a key resolver which retrieves the key used to sign
+// a token from its "jwk" header parameter. As anyone can embed a key into a
+// token, the key is only trusted when its JWK thumbprint (RFC 7638) is allowed.
+type JWKResolver struct {
+	hash    crypto.Hash
+	allowed map[string]bool
+}
+
+// NewJWKResolver creates a new instance of JWKResolver which trusts keys having
+// any of specified base64url encoded thumbprints, computed using specified
+// hash function.
+func NewJWKResolver(hash crypto.Hash, thumbprints ...string) *JWKResolver {
+	r := &JWKResolver{
+		hash:    hash,
+		allowed: make(map[string]bool, len(thumbprints)),
+	}
+	r.Allow(thumbprints...)
+
+	return r
+}
+
+// Allow trusts keys having any of specified base64url encoded thumbprints.
+func (r *JWKResolver) Allow(thumbprints ...string) {
+	for _, v := range thumbprints {
+		r.allowed[v] = true
+	}
+}
+
+// Pin trusts specified key.
+func (r *JWKResolver) Pin(key *jwk.Key) error {
+	thumbprint, err := jwk.ThumbprintID(key, r.hash)
+	if err != nil {
+		return err
+	}
+
+	r.Allow(thumbprint)
+	return nil
+}
+
+// GetKey returns the key embedded into specified header when it is trusted.
+// This method can be used as a GetKeyFunc.
+func (r *JWKResolver) GetKey(header Header) (interface{}, error) {
+	regHeader, ok := header.(*RegHeader)
+	if !ok || regHeader.JWK == nil {
+		return nil, ErrGetKey(header.GetID())
+	}
+	key := regHeader.JWK
+
+	// Only public keys are expected to be embedded
+	if key.IsSymmetric() || len(key.D) > 0 {
+		return nil, ErrUntrustedKey(key.ID)
+	}
+	if len(key.Algorithm) > 0 && key.Algorithm != header.GetAlgorithm() {
+		return nil, ErrUntrustedKey(key.ID)
+	}
+	if err := key.CheckOperation(jwk.KeyOpVerify); err != nil {
+		return nil, err
+	}
+
+	thumbprint, err := jwk.ThumbprintID(key, r.hash)
+	if err != nil {
+		return nil, err
+	}
+	if !r.allowed[thumbprint] {
+		return nil, ErrUntrustedKey(key.ID)
+	}
+
+	return key.Key()
+}
+
+// SetJWK embeds the public representation of specified key into current
+// header, so that the token can be verified by JWKResolver.
+func (h *RegHeader) SetJWK(key *jwk.Key) {
+	public := *key
+	public.RemovePrivateFields()
+	h.JWK = &public
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"testing"
+
+	"github.com/raiqub/jose/converters"
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwk"
+	"github.com/raiqub/jose/jws"
+)
+
+func TestJWKResolver(t *testing.T) {
+	trusted, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	untrusted, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	sum, err := trusted.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Error computing thumbprint: %v", err)
+	}
+	resolver := jws.NewJWKResolver(
+		crypto.SHA256, converters.Base64.FromBytes(sum))
+
+	pinned := jws.NewJWKResolver(crypto.SHA256)
+	if err := pinned.Pin(untrusted); err != nil {
+		t.Fatalf("Error pinning key: %v", err)
+	}
+
+	sign := func(key *jwk.Key, embed *jwk.Key) string {
+		raw, err := key.Key()
+		if err != nil {
+			t.Fatalf("Error loading key: %v", err)
+		}
+
+		token := jws.NewRawToken(jwa.ES256, []byte("payload"))
+		if embed != nil {
+			token.Header.(*jws.RegHeader).SetJWK(embed)
+		}
+		str, err := token.EncodeAndSign(raw)
+		if err != nil {
+			t.Fatalf("Error signing token: %v", err)
+		}
+		return str
+	}
+
+	testCases := []struct {
+		name     string
+		resolver *jws.JWKResolver
+		token    string
+		valid    bool
+	}{
+		{"trusted", resolver, sign(trusted, trusted), true},
+		{"pinned", pinned, sign(untrusted, untrusted), true},
+		{"untrusted", resolver, sign(untrusted, untrusted), false},
+		{"not pinned", pinned, sign(trusted, trusted), false},
+		{"wrong key", resolver, sign(untrusted, trusted), false},
+		{"missing", resolver, sign(trusted, nil), false},
+	}
+
+	for _, tc := range testCases {
+		_, err := jws.DecodeAndVerify(tc.token, nil, tc.resolver.GetKey)
+		if (err == nil) != tc.valid {
+			t.Errorf("Unexpected result for %s key: %v", tc.name, err)
+		}
+	}
+
+	// Private keys must not be embedded
+	token := jws.NewRawToken(jwa.ES256, []byte("payload"))
+	token.Header.(*jws.RegHeader).JWK = trusted
+	raw, _ := trusted.Key()
+	str, err := token.EncodeAndSign(raw)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	_, err = jws.DecodeAndVerify(str, nil, resolver.GetKey)
+	if _, ok := err.(jws.ErrUntrustedKey); !ok {
+		t.Errorf("Unexpected error for embedded private key: %v", err)
+	}
+}
+
+func TestJWKResolverParams(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	resolver := jws.NewJWKResolver(crypto.SHA256)
+	if err := resolver.Pin(key); err != nil {
+		t.Fatalf("Error pinning key: %v", err)
+	}
+
+	token := jws.NewRawToken(jwa.ES256, []byte("payload"))
+	header := token.Header.(*jws.RegHeader)
+	header.SetJWK(key)
+	header.Extra = map[string]interface{}{"tenant": "acme"}
+	raw, _ := key.Key()
+	str, err := token.EncodeAndSign(raw)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	var tenant interface{}
+	_, err = jws.DecodeAndVerify(str, nil,
+		func(h jws.Header) (interface{}, error) {
+			tenant, _ = h.GetExtra("tenant")
+			return resolver.GetKey(h)
+		})
+	if err != nil {
+		t.Fatalf("Error verifying token: %v", err)
+	}
+	if tenant != "acme" {
+		t.Errorf("Unexpected tenant: %v", tenant)
+	}
+}
@@ -0,0 +1,401 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwt"
+)
+
+// A MultiSignedToken represents a token encapsuled by JWS using JSON
+// serialization, which allows multiple signatures over the same payload.
+type MultiSignedToken struct {
+	Payload    jwt.Claims
+	Signatures []Signature
+
+	encodedPayload string
+}
+
+// A Signature represents a single signature of a MultiSignedToken along with
+// its protected and unprotected headers.
+type Signature struct {
+	Header      Header
+	Unprotected map[string]interface{}
+
+	// Verified reports whether current signature was successfully verified
+	// when decoding the token.
+	Verified bool
+
+	protected string
+	value     string
+}
+
+// JSON representation of a token, which may be either general or flattened.
+type jsonToken struct {
+	Payload    string          `json:"payload"`
+	Signatures []jsonSignature `json:"signatures,omitempty"`
+	jsonSignature
+}
+
+// JSON representation of a signature.
+type jsonSignature struct {
+	Protected string                 `json:"protected,omitempty"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Signature string                 `json:"signature,omitempty"`
+}
+
+// NewMultiSignedToken creates a new instance of MultiSignedToken using
+// specified payload.
+func NewMultiSignedToken(payload jwt.Claims) *MultiSignedToken {
+	return &MultiSignedToken{
+		Payload: payload,
+	}
+}
+
+// DecodeAndValidateJSON decodes an existing token using either general or
+// flattened JSON serialization and validates it. The token is valid when at
+// least one of its signatures is verified by a key returned by getKey;
+// signatures whose key could not be retrieved are skipped. Headers are decoded
+// to new instances of the same type as specified header, which defaults to
+// RegHeader when nil. The algorithm of each signature must be defined by its
+// protected header, and unencoded payloads defined by "b64" are not supported.
+func DecodeAndValidateJSON(
+	data []byte,
+	header Header,
+	payload jwt.Claims,
+	getKey GetKeyFunc,
+) (*MultiSignedToken, error) {
+	// ===== DECODING =====
+
+	var in jsonToken
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, ErrInvalidFormat(string(data))
+	}
+
+	sigs := in.Signatures
+	if len(sigs) == 0 {
+		sigs = []jsonSignature{in.jsonSignature}
+	} else if len(in.Signature) > 0 || len(in.Protected) > 0 ||
+		in.Header != nil {
+		return nil, ErrInvalidFormat(string(data))
+	}
+
+	if getKey == nil {
+		return nil, ErrGetKey(string(data))
+	}
+
+	if payload == nil {
+		payload = &jwt.CommonClaims{}
+	}
+
+	t := &MultiSignedToken{
+		Payload:        payload,
+		Signatures:     make([]Signature, 0, len(sigs)),
+		encodedPayload: in.Payload,
+	}
+
+	for _, s := range sigs {
+		if len(s.Signature) == 0 {
+			return nil, ErrInvalidFormat(string(data))
+		}
+
+		protected, merged, err := decodeHeaders(s.Protected, s.Header, header)
+		if err != nil {
+			return nil, ErrInvalidFormat(string(data))
+		}
+		if err := checkSignatureBase64(s); err != nil {
+			return nil, err
+		}
+
+		t.Signatures = append(t.Signatures, Signature{
+			Header:      protected,
+			Unprotected: s.Header,
+			protected:   s.Protected,
+			value:       s.Signature,
+		})
+
+		// ===== VALIDATION =====
+
+		crit, err := checkSignatureCritical(s)
+		if err != nil {
+			continue
+		}
+		method, err := jwa.New(protected.GetAlgorithm())
+		if err != nil {
+			continue
+		}
+		key, err := getKey(merged)
+		if err != nil {
+			continue
+		}
+
+		input := s.Protected + "." + in.Payload
+		if err := method.Verify(input, s.Signature, key); err != nil {
+			continue
+		}
+		if err := crit.Process(merged); err == nil {
+			t.Signatures[len(t.Signatures)-1].Verified = true
+		}
+	}
+
+	if !t.Verified() {
+		return nil, ErrInvalidSignature(string(data))
+	}
+	// Claims are decoded only after the payload is authenticated
+	if err := t.Payload.Decode(in.Payload); err != nil {
+		return nil, err
+	}
+	if !t.Payload.Validate() {
+		return nil, ErrInvalidToken(string(data))
+	}
+
+	return t, nil
+}
+
+// Sign appends a new signature to current token using specified protected
+// header, unprotected header and key. The algorithm must be defined by the
+// protected header, which must not define "b64" as false. The payload must not
+// be changed after first signature is added.
+func (t *MultiSignedToken) Sign(
+	header Header,
+	unprotected map[string]interface{},
+	key interface{},
+) error {
+	if len(header.GetAlgorithm()) == 0 {
+		return ErrUnprotectedParam("alg")
+	}
+
+	if len(t.encodedPayload) == 0 {
+		var buf bytes.Buffer
+		if err := t.Payload.Encode(&buf); err != nil {
+			return err
+		}
+
+		t.encodedPayload = buf.String()
+	}
+
+	var buf bytes.Buffer
+	if err := encodeHeader(&buf, header); err != nil {
+		return err
+	}
+	protected := buf.String()
+
+	if _, _, err := decodeHeaders(protected, unprotected, header); err != nil {
+		return err
+	}
+	if err := checkSignatureBase64(jsonSignature{
+		Protected: protected,
+		Header:    unprotected,
+	}); err != nil {
+		return err
+	}
+
+	method, err := jwa.New(header.GetAlgorithm())
+	if err != nil {
+		return err
+	}
+
+	sig, err := method.Sign(protected+"."+t.encodedPayload, key)
+	if err != nil {
+		return err
+	}
+
+	t.Signatures = append(t.Signatures, Signature{
+		Header:      header,
+		Unprotected: unprotected,
+		protected:   protected,
+		value:       sig,
+	})
+
+	return nil
+}
+
+// Verified returns whether any signature of current token was verified.
+func (t *MultiSignedToken) Verified() bool {
+	for _, s := range t.Signatures {
+		if s.Verified {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EncodeJSON creates the general JSON representation of current token.
+func (t *MultiSignedToken) EncodeJSON() ([]byte, error) {
+	if len(t.Signatures) == 0 {
+		return nil, ErrInvalidFormat("")
+	}
+
+	out := jsonToken{
+		Payload:    t.encodedPayload,
+		Signatures: make([]jsonSignature, 0, len(t.Signatures)),
+	}
+	for _, s := range t.Signatures {
+		out.Signatures = append(out.Signatures, s.toJSON())
+	}
+
+	return json.Marshal(&out)
+}
+
+// EncodeFlattenedJSON creates the flattened JSON representation of current
+// token, which requires current token to have a single signature.
+func (t *MultiSignedToken) EncodeFlattenedJSON() ([]byte, error) {
+	if len(t.Signatures) != 1 {
+		return nil, ErrInvalidFormat("")
+	}
+
+	out := jsonToken{
+		Payload:       t.encodedPayload,
+		jsonSignature: t.Signatures[0].toJSON(),
+	}
+
+	return json.Marshal(&out)
+}
+
+func (s *Signature) toJSON() jsonSignature {
+	return jsonSignature{
+		Protected: s.protected,
+		Header:    s.Unprotected,
+		Signature: s.value,
+	}
+}
+
+// decodeHeaders decodes specified protected header and returns it along with
+// the union of protected and unprotected headers, both as new instances of the
+// same type as specified prototype. The header parameter names of both headers
+// must be disjoint.
+func decodeHeaders(
+	protected string,
+	unprotected map[string]interface{},
+	prototype Header,
+) (Header, Header, error) {
+	header := newHeader(prototype)
+	params := map[string]interface{}{}
+	if len(protected) > 0 {
+		b64in, err := base64.RawURLEncoding.DecodeString(protected)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(b64in, header); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(b64in, &params); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for k, v := range unprotected {
+		if _, ok := params[k]; ok {
+			return nil, nil, ErrInvalidFormat(k)
+		}
+		params[k] = v
+	}
+
+	jmerged, err := json.Marshal(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	merged := newHeader(prototype)
+	if err := json.Unmarshal(jmerged, merged); err != nil {
+		return nil, nil, err
+	}
+
+	return header, merged, nil
+}
+
+// newHeader returns a new empty header of the same type as specified
+// prototype, or a RegHeader when it is nil.
+func newHeader(prototype Header) Header {
+	if prototype == nil {
+		return &RegHeader{}
+	}
+
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return reflect.New(t).Interface().(Header)
+}
+
+// checkSignatureCritical checks the critical parameters of specified
+// signature, which must be integrity protected.
+func checkSignatureCritical(s jsonSignature) (CriticalParams, error) {
+	if _, ok := s.Header[HeaderCritical]; ok {
+		return nil, ErrCritical(HeaderCritical)
+	}
+	if len(s.Protected) == 0 {
+		return nil, nil
+	}
+
+	b64in, err := base64.RawURLEncoding.DecodeString(s.Protected)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkCritical(b64in)
+}
+
+// checkSignatureBase64 returns an error when specified signature defines "b64"
+// as false. Unencoded payloads are not supported by JSON serialization, since
+// its payload is a JWT claims set (RFC 7797 section 7).
+func checkSignatureBase64(s jsonSignature) error {
+	if _, ok := s.Header[HeaderBase64]; ok {
+		return ErrUnprotectedParam(HeaderBase64)
+	}
+	if len(s.Protected) == 0 {
+		return nil
+	}
+
+	b64in, err := base64.RawURLEncoding.DecodeString(s.Protected)
+	if err != nil {
+		return err
+	}
+	var params struct {
+		Base64 *bool `json:"b64"`
+	}
+	if err := json.Unmarshal(b64in, &params); err != nil {
+		return err
+	}
+	if params.Base64 != nil && !*params.Base64 {
+		return ErrUnsupportedHeader(HeaderBase64)
+	}
+
+	return nil
+}
+
+// encodeHeader writes the base64url encoded JSON representation of specified
+// header to w.
+func encodeHeader(w *bytes.Buffer, header Header) error {
+	data, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	b64out := base64.NewEncoder(base64.RawURLEncoding, w)
+	if _, err := b64out.Write(data); err != nil {
+		return err
+	}
+
+	return b64out.Close()
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/hmac"
+	_ "github.com/raiqub/jose/jwa/pkcs1"
+	"github.com/raiqub/jose/jws"
+	"github.com/raiqub/jose/jwt"
+)
+
+var (
+	oldKey = []byte("0123456789abcdef0123456789abcdef")
+	newKey *rsa.PrivateKey
+)
+
+func init() {
+	var err error
+	if newKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		panic(err)
+	}
+}
+
+func newClaims() *jwt.CommonClaims {
+	claims := &jwt.CommonClaims{Subject: "john.doe"}
+	claims.SetExpireAt(time.Now().Add(time.Minute))
+	return claims
+}
+
+func newMultiSigned(t *testing.T) *jws.MultiSignedToken {
+	token := jws.NewMultiSignedToken(newClaims())
+
+	oldHeader := jws.NewHeader(jwa.HS256)
+	oldHeader.ID = "old"
+	if err := token.Sign(oldHeader, nil, oldKey); err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	newHeader := &jws.RegHeader{Algorithm: jwa.RS256}
+	unprotected := map[string]interface{}{"kid": "new"}
+	if err := token.Sign(newHeader, unprotected, newKey); err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	return token
+}
+
+// A decodeRecorder represents claims which record whether they were decoded.
+type decodeRecorder struct {
+	jwt.CommonClaims
+	decoded bool
+}
+
+func (c *decodeRecorder) Decode(s string) error {
+	c.decoded = true
+	return c.CommonClaims.Decode(s)
+}
+
+func getKeyFunc(keys map[string]interface{}) jws.GetKeyFunc {
+	return func(h jws.Header) (interface{}, error) {
+		key, ok := keys[h.GetID()]
+		if !ok {
+			return nil, jws.ErrGetKey(h.GetID())
+		}
+		return key, nil
+	}
+}
+
+func TestMultiSignedToken(t *testing.T) {
+	data, err := newMultiSigned(t).EncodeJSON()
+	if err != nil {
+		t.Fatalf("Error encoding token: %v", err)
+	}
+
+	for _, keys := range []map[string]interface{}{
+		{"old": oldKey},
+		{"new": &newKey.PublicKey},
+		{"old": oldKey, "new": &newKey.PublicKey},
+	} {
+		token, err := jws.DecodeAndValidateJSON(
+			data, nil, nil, getKeyFunc(keys))
+		if err != nil {
+			t.Fatalf("Error validating token: %v", err)
+		}
+		if len(token.Signatures) != 2 {
+			t.Fatalf("Unexpected number of signatures: %d",
+				len(token.Signatures))
+		}
+		for i, kid := range []string{"old", "new"} {
+			_, trusted := keys[kid]
+			if token.Signatures[i].Verified != trusted {
+				t.Errorf("Unexpected verification of signature %s", kid)
+			}
+		}
+		if sub := token.Payload.(*jwt.CommonClaims).Subject; sub != "john.doe" {
+			t.Errorf("Unexpected subject: %s", sub)
+		}
+	}
+
+	_, err = jws.DecodeAndValidateJSON(data, nil, nil,
+		getKeyFunc(map[string]interface{}{"other": oldKey}))
+	if _, ok := err.(jws.ErrInvalidSignature); !ok {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+}
+
+func TestMultiSignedTokenDecodeVerified(t *testing.T) {
+	data, err := newMultiSigned(t).EncodeJSON()
+	if err != nil {
+		t.Fatalf("Error encoding token: %v", err)
+	}
+
+	claims := &decodeRecorder{}
+	_, err = jws.DecodeAndValidateJSON(data, nil, claims,
+		getKeyFunc(map[string]interface{}{"other": oldKey}))
+	if _, ok := err.(jws.ErrInvalidSignature); !ok {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+	if claims.decoded {
+		t.Error("Claims should not be decoded before verification")
+	}
+
+	_, err = jws.DecodeAndValidateJSON(data, nil, claims,
+		getKeyFunc(map[string]interface{}{"old": oldKey}))
+	if err != nil {
+		t.Fatalf("Error validating token: %v", err)
+	}
+	if !claims.decoded || claims.Subject != "john.doe" {
+		t.Errorf("Unexpected decoded claims: %#v", claims)
+	}
+}
+
+func TestMultiSignedTokenTampered(t *testing.T) {
+	data, err := newMultiSigned(t).EncodeJSON()
+	if err != nil {
+		t.Fatalf("Error encoding token: %v", err)
+	}
+
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	payload := raw["payload"]
+	other, _ := json.Marshal(newClaims())
+	raw["payload"] = base64.RawURLEncoding.EncodeToString(other)
+	tampered, _ := json.Marshal(raw)
+
+	keys := map[string]interface{}{"old": oldKey, "new": &newKey.PublicKey}
+	_, err = jws.DecodeAndValidateJSON(
+		tampered, nil, nil, getKeyFunc(keys))
+	if _, ok := err.(jws.ErrInvalidSignature); !ok {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+	raw["payload"] = payload
+
+	// Moving the key identifier to unprotected header of a signature which
+	// already protects it must be refused
+	sigs := raw["signatures"].([]interface{})
+	sigs[0].(map[string]interface{})["header"] = map[string]interface{}{
+		"kid": "new",
+	}
+	ambiguous, _ := json.Marshal(raw)
+	_, err = jws.DecodeAndValidateJSON(
+		ambiguous, nil, nil, getKeyFunc(keys))
+	if _, ok := err.(jws.ErrInvalidFormat); !ok {
+		t.Errorf("Expected invalid format error, got %v", err)
+	}
+}
+
+func TestFlattenedSignedToken(t *testing.T) {
+	token := jws.NewMultiSignedToken(newClaims())
+	if err := token.Sign(jws.NewHeader(jwa.HS256), map[string]interface{}{
+		"kid": "old",
+	}, oldKey); err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	data, err := token.EncodeFlattenedJSON()
+	if err != nil {
+		t.Fatalf("Error encoding token: %v", err)
+	}
+
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	if _, ok := raw["signatures"]; ok {
+		t.Error("Flattened token should not have signatures member")
+	}
+
+	keys := map[string]interface{}{"old": oldKey}
+	result, err := jws.DecodeAndValidateJSON(
+		data, &jws.RegHeader{}, nil, getKeyFunc(keys))
+	if err != nil {
+		t.Fatalf("Error validating token: %v", err)
+	}
+	if result.Signatures[0].Header.GetAlgorithm() != jwa.HS256 {
+		t.Errorf("Unexpected protected header: %#v",
+			result.Signatures[0].Header)
+	}
+
+	if _, err := newMultiSigned(t).EncodeFlattenedJSON(); err == nil {
+		t.Error("Token with multiple signatures cannot be flattened")
+	}
+}
+
+func TestMultiSignedTokenUnprotectedAlg(t *testing.T) {
+	token := jws.NewMultiSignedToken(newClaims())
+	err := token.Sign(&jws.RegHeader{ID: "old"}, map[string]interface{}{
+		"alg": jwa.HS256,
+	}, oldKey)
+	if _, ok := err.(jws.ErrUnprotectedParam); !ok {
+		t.Errorf("Expected unprotected parameter error, got %v", err)
+	}
+
+	// Signature whose algorithm is only defined by its unprotected header
+	payload, err := json.Marshal(newClaims())
+	if err != nil {
+		t.Fatalf("Error encoding claims: %v", err)
+	}
+	segs := strings.Split(signHeader(t, `{"kid":"old"}`, payload), ".")
+	data := `{"payload":"` + segs[1] + `","protected":"` + segs[0] +
+		`","header":{"alg":"HS256"},"signature":"` + segs[2] + `"}`
+
+	keys := map[string]interface{}{"old": oldKey}
+	_, err = jws.DecodeAndValidateJSON(
+		[]byte(data), nil, nil, getKeyFunc(keys))
+	if _, ok := err.(jws.ErrInvalidSignature); !ok {
+		t.Errorf("Expected invalid signature error, got %v", err)
+	}
+}
+
+func TestMultiSignedTokenUnencoded(t *testing.T) {
+	b64 := false
+	header := jws.NewHeader(jwa.HS256)
+	header.Base64 = &b64
+	header.Critical = []string{jws.HeaderBase64}
+	token := jws.NewMultiSignedToken(newClaims())
+	err := token.Sign(header, nil, oldKey)
+	if _, ok := err.(jws.ErrUnsupportedHeader); !ok {
+		t.Errorf("Expected unsupported header error, got %v", err)
+	}
+
+	payload, err := json.Marshal(newClaims())
+	if err != nil {
+		t.Fatalf("Error encoding claims: %v", err)
+	}
+	keys := map[string]interface{}{"old": oldKey}
+	testCases := []struct {
+		header      string
+		unprotected string
+		err         error
+	}{
+		{`{"alg":"HS256","kid":"old","b64":false,"crit":["b64"]}`, "",
+			jws.ErrUnsupportedHeader(jws.HeaderBase64)},
+		{`{"alg":"HS256","kid":"old","crit":["b64"]}`, `{"b64":false}`,
+			jws.ErrUnprotectedParam(jws.HeaderBase64)},
+		{`{"alg":"HS256","kid":"old","b64":true,"crit":["b64"]}`, "", nil},
+	}
+
+	for _, tc := range testCases {
+		segs := strings.Split(signHeader(t, tc.header, payload), ".")
+		data := `{"payload":"` + segs[1] + `","protected":"` + segs[0] +
+			`","signature":"` + segs[2] + `"`
+		if len(tc.unprotected) > 0 {
+			data += `,"header":` + tc.unprotected
+		}
+		data += "}"
+
+		_, err := jws.DecodeAndValidateJSON(
+			[]byte(data), nil, nil, getKeyFunc(keys))
+		if err != tc.err {
+			t.Errorf("Unexpected error for %s: %v", tc.header, err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/raiqub/jose/jwa"
+)
+
+// A RawToken represents a token encapsuled by JWS whose payload is an
+// arbitrary sequence of bytes.
+type RawToken struct {
+	Header  Header
+	Payload []byte
+}
+
+// NewRawToken creates a new instance of RawToken using default header and
+// specified payload.
+func NewRawToken(alg string, payload []byte) *RawToken {
+	return &RawToken{
+		&RegHeader{Algorithm: alg},
+		payload,
+	}
+}
+
+// DecodeAndVerify decodes an existing token and verifies its signature. Unlike
+// DecodeAndValidate the payload is not interpreted in any way.
+func DecodeAndVerify(
+	token string,
+	header Header,
+	getKey GetKeyFunc,
+) (*RawToken, error) {
+	// ===== DECODING =====
+
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 {
+		return nil, ErrInvalidFormat(token)
+	}
+
+	if header == nil {
+		header = &RegHeader{}
+	}
+
+	b64in, err := base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, err
+	}
+	err = ffjson.Unmarshal(b64in, header)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBase64(header); err != nil {
+		return nil, err
+	}
+	crit, err := checkCritical(b64in)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if isPayloadEncoded(header) {
+		if payload, err = base64.RawURLEncoding.DecodeString(segs[1]); err != nil {
+			return nil, err
+		}
+	} else {
+		payload = []byte(segs[1])
+	}
+
+	// ===== VERIFICATION =====
+
+	method, err := jwa.New(header.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	if getKey == nil {
+		return nil, ErrGetKey(token)
+	}
+	if key, err = getKey(header); err != nil {
+		return nil, err
+	}
+
+	lastDotIdx := strings.LastIndex(token, ".")
+	if err := method.Verify(token[:lastDotIdx], segs[2], key); err != nil {
+		return nil, ErrInvalidSignature(token)
+	}
+	if err := crit.Process(header); err != nil {
+		return nil, err
+	}
+
+	return &RawToken{header, payload}, nil
+}
+
+// EncodeAndSign creates a string representation of current token and appends a
+// signature. When header defines "b64" as false the payload is not
+// base64url-encoded, in which case it must not contain any period character.
+func (t *RawToken) EncodeAndSign(key interface{}) (string, error) {
+	encoded := isPayloadEncoded(t.Header)
+	if !encoded {
+		if bytes.IndexByte(t.Payload, '.') >= 0 {
+			return "", ErrInvalidFormat(string(t.Payload))
+		}
+		addBase64Critical(t.Header)
+	}
+
+	var buf bytes.Buffer
+
+	// HEADER
+	if err := encodeHeader(&buf, t.Header); err != nil {
+		return "", err
+	}
+
+	buf.WriteString(".")
+
+	// PAYLOAD
+	if encoded {
+		buf.WriteString(base64.RawURLEncoding.EncodeToString(t.Payload))
+	} else {
+		buf.Write(t.Payload)
+	}
+
+	// SIGNATURE
+	method, err := jwa.New(t.Header.GetAlgorithm())
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := method.Sign(buf.String(), key)
+	if err != nil {
+		return "", err
+	}
+
+	buf.WriteString(".")
+	buf.WriteString(sig)
+
+	return buf.String(), nil
+}
+
+// isPayloadEncoded returns whether the payload of a token having specified
+// header is base64url-encoded. Only RegHeader can define otherwise.
+func isPayloadEncoded(header Header) bool {
+	if h, ok := header.(*RegHeader); ok {
+		return h.IsPayloadEncoded()
+	}
+	return true
+}
+
+// addBase64Critical lists "b64" as critical for specified header, as required
+// by RFC 7797 whenever "b64" is used.
+func addBase64Critical(header Header) {
+	if h, ok := header.(*RegHeader); ok && !h.IsCritical(HeaderBase64) {
+		h.Critical = append(h.Critical, HeaderBase64)
+	}
+}
+
+// checkBase64 returns an error when specified header defines "b64" without
+// listing it as critical.
+func checkBase64(header Header) error {
+	if h, ok := header.(*RegHeader); ok &&
+		h.Base64 != nil && !h.IsCritical(HeaderBase64) {
+		return ErrCritical(HeaderBase64)
+	}
+	return nil
+}
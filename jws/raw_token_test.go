@@ -0,0 +1,88 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jws"
+)
+
+func getOldKey(jws.Header) (interface{}, error) {
+	return oldKey, nil
+}
+
+func TestRawToken(t *testing.T) {
+	payloads := [][]byte{
+		[]byte(`{"name":"manifest","version":3}`),
+		{0x08, 0x96, 0x01, 0x00, 0xff},
+		{},
+	}
+
+	for _, payload := range payloads {
+		token, err := jws.NewRawToken(jwa.HS256, payload).EncodeAndSign(oldKey)
+		if err != nil {
+			t.Fatalf("Error signing raw payload: %v", err)
+		}
+
+		raw, err := jws.DecodeAndVerify(token, nil, getOldKey)
+		if err != nil {
+			t.Fatalf("Error verifying raw token: %v", err)
+		}
+		if !bytes.Equal(raw.Payload, payload) {
+			t.Errorf("Unexpected payload: %v", raw.Payload)
+		}
+
+		segs := strings.Split(token, ".")
+		segs[1] = "e30"
+		_, err = jws.DecodeAndVerify(strings.Join(segs, "."), nil, getOldKey)
+		if _, ok := err.(jws.ErrInvalidSignature); !ok {
+			t.Errorf("Unexpected error verifying modified token: %v", err)
+		}
+	}
+}
+
+func TestRawTokenUnencoded(t *testing.T) {
+	b64 := false
+	header := jws.NewHeader(jwa.HS256)
+	header.Base64 = &b64
+	token := &jws.RawToken{Header: header, Payload: []byte("$02")}
+
+	str, err := token.EncodeAndSign(oldKey)
+	if err != nil {
+		t.Fatalf("Error signing unencoded payload: %v", err)
+	}
+	if segs := strings.Split(str, "."); segs[1] != "$02" {
+		t.Errorf("Unexpected payload segment: %s", segs[1])
+	}
+
+	raw, err := jws.DecodeAndVerify(str, nil, getOldKey)
+	if err != nil {
+		t.Fatalf("Error verifying unencoded token: %v", err)
+	}
+	if string(raw.Payload) != "$02" {
+		t.Errorf("Unexpected payload: %s", raw.Payload)
+	}
+
+	token.Payload = []byte("$.02")
+	if _, err := token.EncodeAndSign(oldKey); err == nil {
+		t.Error("A payload containing periods should not be signed unencoded")
+	}
+}
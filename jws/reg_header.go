@@ -14,29 +14,51 @@
  * limitations under the License.
  */
 
-//go:generate ffjson $GOFILE
-
 package jws
 
+import (
+	"encoding/json"
+
+	"github.com/raiqub/jose/jwk"
+)
+
 const (
 	// JWTHeaderType defines the type name for JWT header.
 	JWTHeaderType = "JOSE"
 )
 
 // A RegHeader represents the JOSE header with all registered parameter
-// names.
+// names. Any other header parameter, such as private ones, is kept by Extra.
+//
+// ffjson: skip
 type RegHeader struct {
 	ID          string   `json:"kid,omitempty"`
 	Type        string   `json:"typ,omitempty"`
 	ContentType string   `json:"cty,omitempty"`
 	Algorithm   string   `json:"alg"`
 	JWKSetURL   string   `json:"jku,omitempty"`
-	JWK         string   `json:"jwk,omitempty"`
+	JWK         *jwk.Key `json:"jwk,omitempty"`
 	X509URL     string   `json:"x5u,omitempty"`
-	X509Chain   string   `json:"x5c,omitempty"`
+	X509Chain   []string `json:"x5c,omitempty"`
 	X509SHA1    string   `json:"x5t,omitempty"`
 	X509SHA256  string   `json:"x5t#S256,omitempty"`
 	Critical    []string `json:"crit,omitempty"`
+	Base64      *bool    `json:"b64,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// regHeader defines the JSON representation of registered parameters of
+// RegHeader.
+type regHeader RegHeader
+
+// Names of header parameters registered by RFC 7515, which are defined by
+// RegHeader fields along with "b64" header parameter.
+// Ref: https://tools.ietf.org/html/rfc7515#section-4.1.
+var registeredParams = map[string]bool{
+	"kid": true, "typ": true, "cty": true, "alg": true, "jku": true,
+	"jwk": true, "x5u": true, "x5c": true, "x5t": true, "x5t#S256": true,
+	HeaderCritical: true,
 }
 
 // NewHeader creates a new instance of Header type.
@@ -62,4 +84,87 @@ func (h *RegHeader) GetJWKSetURL() string {
 	return h.JWKSetURL
 }
 
+// GetExtra returns the value of specified header parameter which is not
+// defined by RegHeader fields, and whether it is defined.
+func (h *RegHeader) GetExtra(name string) (interface{}, bool) {
+	value, ok := h.Extra[name]
+	return value, ok
+}
+
+// IsCritical returns whether specified header parameter is listed as critical.
+func (h *RegHeader) IsCritical(name string) bool {
+	for _, v := range h.Critical {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPayloadEncoded returns whether the payload of current token is
+// base64url-encoded, which is true unless "b64" is defined as false.
+func (h *RegHeader) IsPayloadEncoded() bool {
+	return h.Base64 == nil || *h.Base64
+}
+
+// MarshalJSON returns the JSON representation of current header, including
+// extra header parameters.
+func (h *RegHeader) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*regHeader)(h))
+	if err != nil || len(h.Extra) == 0 {
+		return data, err
+	}
+
+	for name := range h.Extra {
+		if isRegHeaderParam(name) {
+			return nil, ErrDuplicateParam(name)
+		}
+	}
+
+	extra, err := json.Marshal(h.Extra)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both are non-empty JSON objects, since "alg" is always encoded
+	buf := make([]byte, 0, len(data)+len(extra))
+	buf = append(buf, data[:len(data)-1]...)
+	buf = append(buf, ',')
+	buf = append(buf, extra[1:]...)
+
+	return buf, nil
+}
+
+// UnmarshalJSON parses specified JSON representation of a header to current
+// instance. Header parameters not defined by RegHeader are stored into Extra.
+func (h *RegHeader) UnmarshalJSON(data []byte) error {
+	var params map[string]interface{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	h.Extra = nil
+	if err := json.Unmarshal(data, (*regHeader)(h)); err != nil {
+		return err
+	}
+
+	for name, value := range params {
+		if isRegHeaderParam(name) {
+			continue
+		}
+		if h.Extra == nil {
+			h.Extra = make(map[string]interface{})
+		}
+		h.Extra[name] = value
+	}
+
+	return nil
+}
+
 var _ Header = (*RegHeader)(nil)
+
+// isRegHeaderParam reports whether specified header parameter is defined by
+// RegHeader fields.
+func isRegHeaderParam(name string) bool {
+	return registeredParams[name] || name == HeaderBase64
+}
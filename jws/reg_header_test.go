@@ -0,0 +1,83 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"testing"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jws"
+)
+
+func TestHeaderExtra(t *testing.T) {
+	header := jws.NewHeader(jwa.HS256)
+	header.ID = "old"
+	header.Extra = map[string]interface{}{
+		"tenant": "acme",
+		"ver":    2,
+	}
+
+	data, err := ffjson.Marshal(header)
+	if err != nil {
+		t.Fatalf("Error encoding header: %v", err)
+	}
+	expected := `{"kid":"old","typ":"JOSE","alg":"HS256","tenant":"acme","ver":2}`
+	if string(data) != expected {
+		t.Errorf("Unexpected header JSON: %s", data)
+	}
+
+	var decoded jws.RegHeader
+	if err := ffjson.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error decoding header: %v", err)
+	}
+	if decoded.ID != "old" || decoded.Algorithm != jwa.HS256 ||
+		len(decoded.Extra) != 2 || decoded.Extra["tenant"] != "acme" ||
+		decoded.Extra["ver"] != float64(2) {
+		t.Errorf("Unexpected decoded header: %#v", decoded)
+	}
+
+	header.Extra["alg"] = "none"
+	if _, err := ffjson.Marshal(header); err == nil {
+		t.Error("A registered parameter should not be defined by Extra")
+	}
+}
+
+func TestHeaderExtraGetKey(t *testing.T) {
+	token := jws.NewRawToken(jwa.HS256, []byte("payload"))
+	token.Header.(*jws.RegHeader).Extra = map[string]interface{}{
+		"tenant": "acme",
+	}
+
+	str, err := token.EncodeAndSign(oldKey)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+
+	var tenant interface{}
+	_, err = jws.DecodeAndVerify(str, nil,
+		func(h jws.Header) (interface{}, error) {
+			tenant, _ = h.GetExtra("tenant")
+			return oldKey, nil
+		})
+	if err != nil {
+		t.Fatalf("Error verifying token: %v", err)
+	}
+	if tenant != "acme" {
+		t.Errorf("Unexpected tenant: %v", tenant)
+	}
+}
@@ -12,6 +12,18 @@ type Config struct {
 	SetURL    string
 	SignKeyID string
 	Duration  time.Duration
+
+	// EncryptKey defines the recipient key used to encrypt created tokens as
+	// nested JWT. Tokens are only signed when it is nil.
+	EncryptKey *jwk.Key
+
+	// Encryption defines the content encryption algorithm of encrypted
+	// tokens. Defaults to A256GCM.
+	Encryption string
+
+	// EmbedKey defines whether the public signing key is embedded into
+	// created tokens as "jwk" header parameter.
+	EmbedKey bool
 }
 
 // A Cache represents the loaded keys by Signer or Verifier service.
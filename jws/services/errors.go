@@ -29,3 +29,12 @@ type ErrInvalidToken int
 func (e ErrInvalidToken) Error() string {
 	return "Invalid token"
 }
+
+// An ErrUntrustedURL represents an error when a token references a key set URL
+// which is not trusted.
+type ErrUntrustedURL string
+
+// Error returns string representation of current instance error.
+func (e ErrUntrustedURL) Error() string {
+	return fmt.Sprintf("Untrusted key set URL: %s", string(e))
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"crypto"
 	"flag"
 	"fmt"
 	"net/http"
@@ -14,15 +15,22 @@ import (
 	"github.com/raiqub/jose/jwk"
 	"github.com/raiqub/jose/jwk/adapters"
 	"github.com/raiqub/jose/jwk/services"
+	"github.com/raiqub/jose/jws"
 	"github.com/raiqub/jose/jwt"
 	"github.com/raiqub/tlog"
 	"gopkg.in/raiqub/eval.v0"
 	"gopkg.in/raiqub/web.v0"
 
-	// Imports to initialize ECDSA, RSA-PKCS#1 and RSA-PSS algorithms
+	// Imports to initialize ECDSA, EdDSA, RSA-PKCS#1 and RSA-PSS algorithms
 	_ "github.com/raiqub/jose/jwa/ecdsa"
+	_ "github.com/raiqub/jose/jwa/eddsa"
 	_ "github.com/raiqub/jose/jwa/pkcs1"
 	_ "github.com/raiqub/jose/jwa/pss"
+
+	// Imports to initialize encryption algorithms for nested tokens
+	_ "github.com/raiqub/jose/jwa/aesgcm"
+	_ "github.com/raiqub/jose/jwa/ecdhes"
+	_ "github.com/raiqub/jose/jwa/oaep"
 )
 
 const (
@@ -115,6 +123,141 @@ func testCreateAndValidate(alg string, t *testing.T) {
 	//t.Logf("%s token: %s", alg, token)
 }
 
+func TestCreateAndValidateParams(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+
+	adpSet.Add(*key)
+	signer, err := NewSigner(adpSet, Config{
+		Issuer:    issuer,
+		SignKeyID: key.ID,
+		Duration:  duration,
+	})
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+
+	token, err := signer.CreateWithParams(createJWTPayload(),
+		map[string]interface{}{"tenant": "acme"})
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+
+	verifier := &Verifier{
+		issuers: []string{issuer},
+		keys:    map[string]*Cache{key.ID: &signer.keyCache},
+	}
+	vToken, err := verifier.Verify(token, nil, nil)
+	if err != nil {
+		t.Fatalf("The token cannot be validated: %v", err)
+	}
+
+	header := vToken.Header.(*jws.RegHeader)
+	if header.Extra["tenant"] != "acme" {
+		t.Errorf("Unexpected header parameters: %v", header.Extra)
+	}
+}
+
+func TestCreateAndValidateEmbeddedKey(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+
+	adpSet.Add(*key)
+	signer, err := NewSigner(adpSet, Config{
+		Issuer:    issuer,
+		SignKeyID: key.ID,
+		Duration:  duration,
+		EmbedKey:  true,
+	})
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+
+	token, err := signer.Create(createJWTPayload())
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+
+	resolver := jws.NewJWKResolver(crypto.SHA256)
+	if err := resolver.Pin(key); err != nil {
+		t.Fatalf("Error pinning key: %v", err)
+	}
+	vToken, err := jws.DecodeAndValidate(token, nil, nil, resolver.GetKey)
+	if err != nil {
+		t.Fatalf("The token cannot be validated: %v", err)
+	}
+
+	if embedded := vToken.Header.(*jws.RegHeader).JWK; embedded == nil ||
+		len(embedded.D) > 0 {
+		t.Errorf("Unexpected embedded key: %v", embedded)
+	}
+}
+
+func TestSignerKeyOperations(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	key.KeyOps = []string{jwk.KeyOpVerify}
+
+	adpSet.Add(*key)
+	_, err = NewSigner(adpSet, Config{
+		Issuer:    issuer,
+		SignKeyID: key.ID,
+		Duration:  duration,
+	})
+	if _, ok := err.(jwk.ErrOperationNotPermitted); !ok {
+		t.Errorf("Unexpected error creating signer: %v", err)
+	}
+}
+
+func TestVerifierInvalidKey(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	invalid := jwk.Key{
+		Type:      jwk.KeyTypeSymmetric,
+		ID:        "invalid",
+		Algorithm: jwa.HS256,
+		K:         "c2hvcnQ",
+	}
+
+	keys, errs := loadKeys(
+		[]jwk.Key{invalid, *key}, tlog.NewTracerNop(), "Verifier")
+	if _, ok := keys[key.ID]; !ok || len(keys) != 1 {
+		t.Fatalf("Unexpected loaded keys: %v", keys)
+	}
+	if _, ok := errs[invalid.ID]; !ok {
+		t.Fatal("The invalid key should be skipped")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			jwkset := jwk.Set{Keys: []jwk.Key{invalid, *key}}
+			web.JSONWrite(w, http.StatusOK, jwkset)
+		}))
+	defer ts.Close()
+
+	verifier, err := NewVerifier(services.NewSetClient(ts.URL), nil, issuer)
+	if err != nil {
+		t.Fatalf("Error creating verifier: %v", err)
+	}
+	if _, ok := verifier.keys[key.ID]; !ok {
+		t.Error("The valid key should be loaded")
+	}
+
+	verifier.invalid = errs
+	header := &jws.RegHeader{ID: invalid.ID, Algorithm: jwa.HS256}
+	if _, err := verifier.getKey(header); err != errs[invalid.ID] {
+		t.Errorf("Unexpected error for invalid key: %v", err)
+	}
+}
+
 func TestCreateAndValidateES256(t *testing.T) {
 	testCreateAndValidate(jwa.ES256, t)
 }
@@ -127,6 +270,14 @@ func TestCreateAndValidateES512(t *testing.T) {
 	testCreateAndValidate(jwa.ES512, t)
 }
 
+func TestCreateAndValidateES256K(t *testing.T) {
+	testCreateAndValidate(jwa.ES256K, t)
+}
+
+func TestCreateAndValidateEdDSA(t *testing.T) {
+	testCreateAndValidate(jwa.EdDSA, t)
+}
+
 func TestCreateAndValidateRS256(t *testing.T) {
 	testCreateAndValidate(jwa.RS256, t)
 }
@@ -151,6 +302,87 @@ func TestCreateAndValidatePS512(t *testing.T) {
 	testCreateAndValidate(jwa.PS512, t)
 }
 
+func testCreateAndValidateNested(
+	sigAlg, encAlg string,
+	encBits int,
+	t *testing.T,
+) {
+	key, err := jwk.GenerateKey(sigAlg, keySize, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	encKey, err := jwk.GenerateKey(encAlg, encBits, 1)
+	if err != nil {
+		t.Fatalf("Error generating new encryption key: %v", err)
+	}
+	pubEncKey := *encKey
+	pubEncKey.RemovePrivateFields()
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			jwkset := jwk.Set{Keys: []jwk.Key{*key}}
+			web.JSONWrite(w, http.StatusOK, jwkset)
+		}))
+	defer ts.Close()
+
+	adpSet.Add(*key)
+	signer, err := NewSigner(adpSet, Config{
+		Issuer:     issuer,
+		SetURL:     ts.URL,
+		SignKeyID:  key.ID,
+		Duration:   duration,
+		EncryptKey: &pubEncKey,
+	})
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+
+	token, err := signer.Create(createJWTPayload())
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+	if strings.Count(token, ".") != 4 {
+		t.Fatalf("Token is not encrypted: %s", token)
+	}
+
+	cliJWKSet := services.NewSetClient(ts.URL)
+	verifier, err := NewVerifier(cliJWKSet, nil, issuer)
+	if err != nil {
+		t.Fatalf("Error creating verifier: %v", err)
+	}
+	if _, err := verifier.Verify(token, nil, nil); err == nil {
+		t.Error("Encrypted token should not be accepted without decryption key")
+	}
+
+	if err := verifier.EnableDecryption(true, *encKey); err != nil {
+		t.Fatalf("Error enabling decryption: %v", err)
+	}
+	vToken, err := verifier.Verify(token, nil, nil)
+	if err != nil {
+		t.Fatalf("The token cannot be validated: %v", err)
+	}
+	if vToken.Header.GetID() != key.ID {
+		t.Errorf("Unexpected signing key: %s", vToken.Header.GetID())
+	}
+
+	signer.encCache = nil
+	plain, err := signer.Create(createJWTPayload())
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+	if _, err := verifier.Verify(plain, nil, nil); err == nil {
+		t.Error("Signed only token should not be accepted")
+	}
+}
+
+func TestCreateAndValidateNestedRSAOAEP(t *testing.T) {
+	testCreateAndValidateNested(jwa.RS256, jwa.RSAOAEP256, keySize, t)
+}
+
+func TestCreateAndValidateNestedECDHES(t *testing.T) {
+	testCreateAndValidateNested(jwa.ES256, jwa.ECDHESA256KW, 384, t)
+}
+
 func createJWTPayload() *jwt.CommonClaims {
 	return &jwt.CommonClaims{
 		Audience: audience,
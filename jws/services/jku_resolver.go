@@ -0,0 +1,288 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raiqub/jose/jwk"
+	jwkservices "github.com/raiqub/jose/jwk/services"
+	"github.com/raiqub/jose/jws"
+	"github.com/raiqub/tlog"
+)
+
+const (
+	// DefaultJKUCacheDuration defines the default duration which key sets
+	// fetched by JKUResolver are cached.
+	DefaultJKUCacheDuration = time.Hour
+
+	// DefaultJKUFailureCacheDuration defines the default duration which
+	// failures to fetch a key set are cached by JKUResolver.
+	DefaultJKUFailureCacheDuration = 30 * time.Second
+
+	// DefaultJKUMaxSets defines the default maximum number of key sets cached
+	// by JKUResolver.
+	DefaultJKUMaxSets = 100
+
+	// DefaultJKUTimeout defines the default time limit for requests sent by
+	// JKUResolver to fetch a key set.
+	DefaultJKUTimeout = 10 * time.Second
+
+	// Maximum number of redirects followed when fetching a key set.
+	maxJKURedirects = 10
+)
+
+// A JKUResolver represents a key resolver which retrieves the key used to sign
+// a token from the key set referenced by its "jku" header parameter. Only URLs
+// matching the allow-list are fetched, including any redirect, and fetched key
+// sets are cached by URL.
+type JKUResolver struct {
+	// CacheDuration defines how long fetched key sets are cached.
+	CacheDuration time.Duration
+
+	// FailureCacheDuration defines how long failures to fetch a key set are
+	// cached, avoiding to send a request for every token referencing an
+	// unavailable key set.
+	FailureCacheDuration time.Duration
+
+	// MaxSets defines the maximum number of cached key sets. The oldest
+	// cached key set is discarded when the limit is reached.
+	MaxSets int
+
+	allowed []*url.URL
+	client  *http.Client
+	tracer  tlog.Tracer
+
+	mutex sync.Mutex
+	sets  map[string]*jkuSet
+	order []string
+}
+
+// A jkuSet represents a key set fetched from a URL. Its mutex is held while
+// the key set is fetched, so concurrent requests for the same URL wait for a
+// single fetch without blocking requests for other URLs.
+type jkuSet struct {
+	mutex     sync.Mutex
+	keys      *jkuKeys
+	err       error
+	expiresAt time.Time
+}
+
+// A jkuKeys represents the keys loaded from a fetched key set. It is never
+// modified once loaded, thus it can be read without holding the mutex of its
+// key set while the key set is fetched again.
+type jkuKeys struct {
+	keys    map[string]*Cache
+	invalid map[string]error
+}
+
+// NewJKUResolver creates a new instance of JKUResolver which trusts specified
+// URLs. Each allowed entry is either a host name, optionally followed by a port
+// number, which trusts any HTTPS URL of that host; or an absolute URL, which
+// trusts any URL having the same scheme and host and whose path starts with
+// its path when it ends with a slash, or whose path is the same otherwise.
+func NewJKUResolver(
+	tracer tlog.Tracer,
+	allowed ...string,
+) (*JKUResolver, error) {
+	if tracer == nil {
+		tracer = tlog.NewTracerNop()
+	}
+
+	r := &JKUResolver{
+		CacheDuration:        DefaultJKUCacheDuration,
+		FailureCacheDuration: DefaultJKUFailureCacheDuration,
+		MaxSets:              DefaultJKUMaxSets,
+		allowed:              make([]*url.URL, 0, len(allowed)),
+		tracer:               tracer,
+		sets:                 make(map[string]*jkuSet),
+	}
+
+	for _, v := range allowed {
+		if !strings.Contains(v, "://") {
+			v = "https://" + v + "/"
+		}
+
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		if !u.IsAbs() || len(u.Host) == 0 || u.User != nil ||
+			len(u.RawQuery) > 0 || u.ForceQuery || len(u.Fragment) > 0 {
+			return nil, ErrUntrustedURL(v)
+		}
+		if len(u.Path) == 0 {
+			u.Path = "/"
+		}
+		r.allowed = append(r.allowed, u)
+	}
+
+	r.client = &http.Client{
+		Timeout: DefaultJKUTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxJKURedirects {
+				return ErrUntrustedURL(req.URL.String())
+			}
+			if !r.IsAllowed(req.URL) {
+				return ErrUntrustedURL(req.URL.String())
+			}
+			return nil
+		},
+	}
+
+	return r, nil
+}
+
+// IsAllowed returns whether specified URL matches the allow-list. URLs having
+// user information, query or fragment are never allowed.
+func (r *JKUResolver) IsAllowed(u *url.URL) bool {
+	if u.User != nil || len(u.RawQuery) > 0 || u.ForceQuery ||
+		len(u.Fragment) > 0 || strings.Contains(u.Path, "..") {
+		return false
+	}
+
+	path := u.Path
+	if len(path) == 0 {
+		path = "/"
+	}
+
+	for _, a := range r.allowed {
+		if !strings.EqualFold(a.Scheme, u.Scheme) ||
+			!strings.EqualFold(a.Host, u.Host) {
+			continue
+		}
+
+		if path == a.Path || (strings.HasSuffix(a.Path, "/") &&
+			strings.HasPrefix(path, a.Path)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetKey returns the key identified by specified header from the key set
+// referenced by its "jku" header parameter. This method can be used as a
+// jws.GetKeyFunc.
+func (r *JKUResolver) GetKey(header jws.Header) (interface{}, error) {
+	rawURL := header.GetJWKSetURL()
+	u, err := url.Parse(rawURL)
+	if err != nil || len(rawURL) == 0 || !r.IsAllowed(u) {
+		return nil, ErrUntrustedURL(rawURL)
+	}
+
+	keys, err := r.getSet(canonicalURL(u))
+	if err != nil {
+		return nil, err
+	}
+	if err, ok := keys.invalid[header.GetID()]; ok {
+		return nil, err
+	}
+
+	key, ok := keys.keys[header.GetID()]
+	if !ok {
+		return nil, ErrInvalidKeyID(header.GetID())
+	}
+	if header.GetAlgorithm() != key.JWK.Algorithm {
+		return nil, ErrUnexpectedAlg(header.GetAlgorithm())
+	}
+	if err := key.JWK.CheckOperation(jwk.KeyOpVerify); err != nil {
+		return nil, err
+	}
+
+	return key.RawKey, nil
+}
+
+// getSet returns the keys of specified key set URL, fetching them when they are
+// not cached. Returned keys must not be modified.
+func (r *JKUResolver) getSet(setURL string) (*jkuKeys, error) {
+	set := r.getEntry(setURL)
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	now := time.Now()
+	if now.Before(set.expiresAt) {
+		return set.keys, set.err
+	}
+
+	set.keys, set.err = r.fetchSet(setURL)
+	if set.err != nil {
+		set.expiresAt = now.Add(r.FailureCacheDuration)
+	} else {
+		set.expiresAt = now.Add(r.CacheDuration)
+	}
+
+	return set.keys, set.err
+}
+
+// getEntry returns the cache entry of specified key set URL, creating it when
+// not found. The oldest entries are discarded when the cache is full.
+func (r *JKUResolver) getEntry(setURL string) *jkuSet {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if set, ok := r.sets[setURL]; ok {
+		return set
+	}
+
+	for len(r.order) > 0 && len(r.order) >= r.MaxSets {
+		delete(r.sets, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	set := &jkuSet{}
+	r.sets[setURL] = set
+	r.order = append(r.order, setURL)
+	return set
+}
+
+// fetchSet fetches and loads the keys of specified key set URL. Invalid keys
+// are skipped.
+func (r *JKUResolver) fetchSet(setURL string) (*jkuKeys, error) {
+	client := jwkservices.NewSetClientWithHTTP(setURL, r.client)
+	jwkset, err := client.GetCerts(r.tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := &jkuKeys{}
+	keys.keys, keys.invalid = loadKeys(jwkset.Keys, r.tracer, "JKUResolver")
+	r.tracer.AddEntry(
+		tlog.LevelInfo, "jwkset_fetched", "JWK set fetched: "+setURL,
+		0, nil, "JKUResolver", "fetchSet")
+
+	return keys, nil
+}
+
+// canonicalURL returns the canonical form of specified allowed URL, which is
+// used to fetch and cache its key set.
+func canonicalURL(u *url.URL) string {
+	c := url.URL{
+		Scheme: strings.ToLower(u.Scheme),
+		Host:   strings.ToLower(u.Host),
+		Path:   u.Path,
+	}
+	if len(c.Path) == 0 {
+		c.Path = "/"
+	}
+
+	return c.String()
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwk"
+	"github.com/raiqub/jose/jws"
+	"gopkg.in/raiqub/web.v0"
+)
+
+func TestJKUResolver(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	jwkset := jwk.Set{Keys: []jwk.Key{*key}}
+
+	evil := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			web.JSONWrite(w, http.StatusOK, jwkset)
+		}))
+	defer evil.Close()
+
+	fetches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/keys/", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		web.JSONWrite(w, http.StatusOK, jwkset)
+	})
+	mux.HandleFunc("/keys/evil", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, evil.URL+"/keys/", http.StatusFound)
+	})
+	mux.HandleFunc("/keys/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/keys/", http.StatusFound)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		web.JSONWrite(w, http.StatusOK, jwkset)
+	})
+	mux.HandleFunc("/jwks-evil", func(w http.ResponseWriter, r *http.Request) {
+		web.JSONWrite(w, http.StatusOK, jwkset)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resolver, err := NewJKUResolver(
+		nil, ts.URL+"/keys/", ts.URL+"/jwks", "auth.example.com")
+	if err != nil {
+		t.Fatalf("Error creating resolver: %v", err)
+	}
+
+	adpSet.Add(*key)
+	signer, err := NewSigner(adpSet, Config{
+		Issuer:    issuer,
+		SetURL:    ts.URL + "/keys/",
+		SignKeyID: key.ID,
+		Duration:  duration,
+	})
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+	token, err := signer.Create(createJWTPayload())
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+
+	verifier := &Verifier{
+		issuers: []string{issuer},
+		keys:    map[string]*Cache{},
+	}
+	if _, err := verifier.Verify(token, nil, nil); err == nil {
+		t.Error("The key set URL should not be fetched unless enabled")
+	}
+
+	verifier.EnableJKU(resolver)
+	for i := 0; i < 2; i++ {
+		if _, err := verifier.Verify(token, nil, nil); err != nil {
+			t.Fatalf("The token cannot be validated: %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("The key set should be fetched once, got %d", fetches)
+	}
+
+	testCases := []struct {
+		setURL string
+		valid  bool
+	}{
+		{ts.URL + "/keys/moved", true},
+		{ts.URL + "/keys/evil", false},
+		{ts.URL + "/keys/../redirect", false},
+		{ts.URL + "/keys/?x=1", false},
+		{ts.URL + "/jwks", true},
+		{ts.URL + "/jwks-evil", false},
+		{ts.URL + "/jwks/", false},
+		{evil.URL + "/keys/", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		header := &jws.RegHeader{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			JWKSetURL: tc.setURL,
+		}
+		_, err := resolver.GetKey(header)
+		if (err == nil) != tc.valid {
+			t.Errorf("Unexpected result for %q: %v", tc.setURL, err)
+		}
+	}
+
+	for rawURL, allowed := range map[string]bool{
+		"https://auth.example.com/jwks":          true,
+		"http://auth.example.com/jwks":           false,
+		"https://auth.example.com.evil.com/jwks": false,
+		"https://user@auth.example.com/jwks":     false,
+		"https://auth.example.com/jwks?x=1":      false,
+		"https://auth.example.com":               true,
+	} {
+		u, _ := url.Parse(rawURL)
+		if resolver.IsAllowed(u) != allowed {
+			t.Errorf("Unexpected allow-list result for %s", rawURL)
+		}
+	}
+}
+
+func TestJKUResolverCache(t *testing.T) {
+	fetches := 0
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+	defer ts.Close()
+
+	resolver, err := NewJKUResolver(nil, ts.URL+"/")
+	if err != nil {
+		t.Fatalf("Error creating resolver: %v", err)
+	}
+	resolver.MaxSets = 2
+
+	for _, path := range []string{"/a", "/a", "/A", "/b", "/c"} {
+		header := &jws.RegHeader{ID: "key", JWKSetURL: ts.URL + path}
+		if _, err := resolver.GetKey(header); err == nil {
+			t.Errorf("Unavailable key set should not be resolved: %s", path)
+		}
+	}
+
+	if fetches != 4 {
+		t.Errorf("Failed fetches should be cached, got %d fetches", fetches)
+	}
+	if len(resolver.sets) != 2 || len(resolver.order) != 2 {
+		t.Errorf("Unexpected cached key sets count: %d", len(resolver.sets))
+	}
+}
+
+func TestJKUResolverConcurrent(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	jwkset := jwk.Set{Keys: []jwk.Key{*key}}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			web.JSONWrite(w, http.StatusOK, jwkset)
+		}))
+	defer ts.Close()
+
+	resolver, err := NewJKUResolver(nil, ts.URL+"/")
+	if err != nil {
+		t.Fatalf("Error creating resolver: %v", err)
+	}
+	// Fetch the key set again on every request
+	resolver.CacheDuration = 0
+
+	header := &jws.RegHeader{
+		ID:        key.ID,
+		Algorithm: key.Algorithm,
+		JWKSetURL: ts.URL + "/jwks",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := resolver.GetKey(header); err != nil {
+					t.Errorf("Error resolving key: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestJKUResolverParams(t *testing.T) {
+	key, err := jwk.GenerateKey(jwa.ES256, 0, 1)
+	if err != nil {
+		t.Fatalf("Error generating new key: %v", err)
+	}
+	jwkset := jwk.Set{Keys: []jwk.Key{*key}}
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			web.JSONWrite(w, http.StatusOK, jwkset)
+		}))
+	defer ts.Close()
+
+	resolver, err := NewJKUResolver(nil, ts.URL+"/")
+	if err != nil {
+		t.Fatalf("Error creating resolver: %v", err)
+	}
+
+	adpSet.Add(*key)
+	signer, err := NewSigner(adpSet, Config{
+		Issuer:    issuer,
+		SetURL:    ts.URL + "/jwks",
+		SignKeyID: key.ID,
+		Duration:  duration,
+	})
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+	token, err := signer.CreateWithParams(createJWTPayload(),
+		map[string]interface{}{"tenant": "acme"})
+	if err != nil {
+		t.Fatalf("Error creating token: %v", err)
+	}
+
+	var tenant interface{}
+	_, err = jws.DecodeAndVerify(token, nil,
+		func(h jws.Header) (interface{}, error) {
+			tenant, _ = h.GetExtra("tenant")
+			return resolver.GetKey(h)
+		})
+	if err != nil {
+		t.Fatalf("The token cannot be verified: %v", err)
+	}
+	if tenant != "acme" {
+		t.Errorf("Unexpected tenant: %v", tenant)
+	}
+
+	verifier := &Verifier{
+		issuers: []string{issuer},
+		keys:    map[string]*Cache{},
+	}
+	verifier.EnableJKU(resolver)
+	vToken, err := verifier.Verify(token, nil, nil)
+	if err != nil {
+		t.Fatalf("The token cannot be validated: %v", err)
+	}
+	if v, ok := vToken.Header.GetExtra("tenant"); !ok || v != "acme" {
+		t.Errorf("Unexpected tenant: %v", v)
+	}
+}
@@ -19,6 +19,9 @@ package services
 import (
 	"time"
 
+	"github.com/raiqub/jose/jwa"
+	"github.com/raiqub/jose/jwe"
+	"github.com/raiqub/jose/jwk"
 	"github.com/raiqub/jose/jwk/adapters"
 	"github.com/raiqub/jose/jws"
 )
@@ -28,6 +31,7 @@ type Signer struct {
 	adpSet   adapters.Set
 	config   Config
 	keyCache Cache
+	encCache *Cache
 }
 
 // NewSigner creates a new instance of Signer service.
@@ -36,20 +40,49 @@ func NewSigner(adpSet adapters.Set, config Config) (*Signer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := dbKey.CheckOperation(jwk.KeyOpSign); err != nil {
+		return nil, err
+	}
 	rawKey, err := dbKey.Key()
 	if err != nil {
 		return nil, err
 	}
 
+	var encCache *Cache
+	if config.EncryptKey != nil {
+		if err := config.EncryptKey.CheckEncrypt(); err != nil {
+			return nil, err
+		}
+		rawEncKey, err := config.EncryptKey.Key()
+		if err != nil {
+			return nil, err
+		}
+		if len(config.Encryption) == 0 {
+			config.Encryption = jwa.A256GCM
+		}
+
+		encCache = &Cache{*config.EncryptKey, rawEncKey}
+	}
+
 	return &Signer{
 		adpSet,
 		config,
 		Cache{*dbKey, rawKey},
+		encCache,
 	}, nil
 }
 
 // Create a new token and sign it.
 func (s *Signer) Create(payload ClaimsSecure) (string, error) {
+	return s.CreateWithParams(payload, nil)
+}
+
+// CreateWithParams creates a new token having specified additional header
+// parameters and sign it.
+func (s *Signer) CreateWithParams(
+	payload ClaimsSecure,
+	params map[string]interface{},
+) (string, error) {
 	now := time.Now()
 
 	payload.SetIssuer(s.config.Issuer)
@@ -62,12 +95,26 @@ func (s *Signer) Create(payload ClaimsSecure) (string, error) {
 		Type:      jws.JWTHeaderType,
 		Algorithm: s.keyCache.JWK.Algorithm,
 		JWKSetURL: s.config.SetURL,
+		Extra:     params,
+	}
+	if s.config.EmbedKey {
+		header.SetJWK(&s.keyCache.JWK)
 	}
 
 	token := jws.SignedToken{
 		Header:  header,
 		Payload: payload,
 	}
+
+	if s.encCache != nil {
+		encHeader := jwe.NewHeader(
+			s.encCache.JWK.Algorithm, s.config.Encryption)
+		encHeader.ID = s.encCache.JWK.ID
+
+		return jwe.EncryptSigned(
+			&token, s.keyCache.RawKey, encHeader, s.encCache.RawKey)
+	}
+
 	out, err := token.EncodeAndSign(s.keyCache.RawKey)
 	if err != nil {
 		return "", err
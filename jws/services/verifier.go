@@ -17,6 +17,10 @@
 package services
 
 import (
+	"strings"
+
+	"github.com/raiqub/jose/jwe"
+	"github.com/raiqub/jose/jwk"
 	jwkservices "github.com/raiqub/jose/jwk/services"
 	"github.com/raiqub/jose/jws"
 	"github.com/raiqub/tlog"
@@ -27,6 +31,12 @@ import (
 type Verifier struct {
 	issuers []string
 	keys    map[string]*Cache
+	invalid map[string]error
+
+	decKeys           map[string]*Cache
+	requireEncryption bool
+
+	jku *JKUResolver
 }
 
 // NewVerifier creates a new instance of Verifier service.
@@ -44,48 +54,66 @@ func NewVerifier(
 		return nil, err
 	}
 
-	result := &Verifier{
-		issuers,
-		make(map[string]*Cache, 0),
-	}
+	keys, invalid := loadKeys(jwkset.Keys, tracer, "Verifier")
+	return &Verifier{
+		issuers: issuers,
+		keys:    keys,
+		invalid: invalid,
+	}, nil
+}
 
-	for _, k := range jwkset.Keys {
+// EnableDecryption allows current verifier to accept nested JWT tokens
+// encrypted using any of specified keys. Tokens which are only signed are
+// refused when encryption is required.
+func (v *Verifier) EnableDecryption(required bool, keys ...jwk.Key) error {
+	decKeys := make(map[string]*Cache, len(keys))
+	for _, k := range keys {
+		if err := k.Validate(); err != nil {
+			return err
+		}
+		if err := k.CheckDecrypt(); err != nil {
+			return err
+		}
 		rawKey, err := k.Key()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		result.keys[k.ID] = &Cache{k, rawKey}
-		tracer.AddEntry(
-			tlog.LevelInfo, "jwkset_key_loaded", "JWK set key loaded: "+k.ID,
-			0, nil, "Verifier", "NewVerifier")
+		decKeys[k.ID] = &Cache{k, rawKey}
 	}
 
-	return result, nil
+	v.decKeys = decKeys
+	v.requireEncryption = required
+	return nil
+}
+
+// EnableJKU allows current verifier to accept tokens signed by keys which are
+// not previously loaded, retrieving them from the key set referenced by "jku"
+// header parameter when trusted by specified resolver.
+func (v *Verifier) EnableJKU(resolver *JKUResolver) {
+	v.jku = resolver
 }
 
-// Verify specified token and decode it.
+// Verify specified token and decode it. Nested JWT tokens are decrypted when
+// decryption is enabled. Additional header parameters are available from the
+// header of returned token through its GetExtra method.
 func (v *Verifier) Verify(
 	rawtoken string,
 	header jws.Header,
 	payload ClaimsSecure,
 ) (*jws.SignedToken, error) {
-	token, err := jws.DecodeAndValidate(
-		rawtoken, header, payload,
-		func(header jws.Header) (interface{}, error) {
-			var key *Cache
-			var ok bool
-
-			if key, ok = v.keys[header.GetID()]; !ok {
-				return nil, ErrInvalidKeyID(header.GetID())
-			}
-			if header.GetAlgorithm() != key.JWK.Algorithm {
-				return nil, ErrUnexpectedAlg(header.GetAlgorithm())
-			}
-
-			return key.RawKey, nil
-		},
-	)
+	var token *jws.SignedToken
+	var err error
+
+	switch {
+	case v.decKeys != nil && strings.Count(rawtoken, ".") == 4:
+		token, err = jwe.DecryptAndValidate(
+			rawtoken, v.getDecryptionKey, header, payload, v.getKey)
+	case v.requireEncryption:
+		return nil, ErrInvalidToken(0)
+	default:
+		token, err = jws.DecodeAndValidate(rawtoken, header, payload, v.getKey)
+	}
 
 	if err != nil {
 		return nil, err
@@ -102,3 +130,69 @@ func (v *Verifier) Verify(
 
 	return token, nil
 }
+
+func (v *Verifier) getKey(header jws.Header) (interface{}, error) {
+	if err, ok := v.invalid[header.GetID()]; ok {
+		return nil, err
+	}
+
+	key, ok := v.keys[header.GetID()]
+	if !ok && v.jku != nil && len(header.GetJWKSetURL()) > 0 {
+		return v.jku.GetKey(header)
+	}
+	if !ok {
+		return nil, ErrInvalidKeyID(header.GetID())
+	}
+	if header.GetAlgorithm() != key.JWK.Algorithm {
+		return nil, ErrUnexpectedAlg(header.GetAlgorithm())
+	}
+	if err := key.JWK.CheckOperation(jwk.KeyOpVerify); err != nil {
+		return nil, err
+	}
+
+	return key.RawKey, nil
+}
+
+func (v *Verifier) getDecryptionKey(header *jwe.RegHeader) (interface{}, error) {
+	key, ok := v.decKeys[header.GetID()]
+	if !ok {
+		return nil, ErrInvalidKeyID(header.GetID())
+	}
+	if header.GetAlgorithm() != key.JWK.Algorithm {
+		return nil, ErrUnexpectedAlg(header.GetAlgorithm())
+	}
+
+	return key.RawKey, nil
+}
+
+// loadKeys loads specified keys indexed by their IDs. Invalid keys are skipped
+// and their errors are returned by key ID instead, so that they are only
+// reported when an invalid key is requested.
+func loadKeys(
+	keys []jwk.Key,
+	tracer tlog.Tracer,
+	caller string,
+) (map[string]*Cache, map[string]error) {
+	result := make(map[string]*Cache, len(keys))
+	invalid := make(map[string]error)
+	for _, k := range keys {
+		rawKey, err := k.Key()
+		if err == nil {
+			err = k.Validate()
+		}
+		if err != nil {
+			invalid[k.ID] = err
+			tracer.AddEntry(
+				tlog.LevelWarn, "invalid_key", "Invalid key skipped: "+k.ID,
+				0, err, caller, "loadKeys")
+			continue
+		}
+
+		result[k.ID] = &Cache{k, rawKey}
+		tracer.AddEntry(
+			tlog.LevelInfo, "jwkset_key_loaded", "JWK set key loaded: "+k.ID,
+			0, nil, caller, "loadKeys")
+	}
+
+	return result, invalid
+}
@@ -19,9 +19,7 @@ package jws
 import (
 	"bytes"
 	"encoding/base64"
-	"strings"
 
-	"github.com/pquerna/ffjson/ffjson"
 	"github.com/raiqub/jose/jwa"
 	"github.com/raiqub/jose/jwt"
 )
@@ -48,103 +46,51 @@ func NewSignedToken(alg string) *SignedToken {
 	}
 }
 
-// DecodeAndValidate decodes an existing token and validates it.
+// DecodeAndValidate decodes an existing token, verifies its signature and
+// validates its claims.
 func DecodeAndValidate(
 	token string,
 	header Header,
 	payload jwt.Claims,
 	getKey GetKeyFunc,
 ) (*SignedToken, error) {
-	// ===== DECODING =====
-
-	segs := strings.Split(token, ".")
-	if len(segs) != 3 {
-		return nil, ErrInvalidFormat(token)
+	raw, err := DecodeAndVerify(token, header, getKey)
+	if err != nil {
+		return nil, err
 	}
-	lastDotIdx := strings.LastIndex(token, ".")
 
-	if header == nil {
-		header = &RegHeader{}
-	}
 	if payload == nil {
 		payload = &jwt.CommonClaims{}
 	}
-
-	b64in, err := base64.RawURLEncoding.DecodeString(segs[0])
-	if err != nil {
-		return nil, err
-	}
-	err = ffjson.Unmarshal(b64in, header)
-	if err != nil {
+	// Claims are decoded from their base64url representation
+	b64payload := base64.RawURLEncoding.EncodeToString(raw.Payload)
+	if err := payload.Decode(b64payload); err != nil {
 		return nil, err
 	}
 
-	if err := payload.Decode(segs[1]); err != nil {
-		return nil, err
-	}
-
-	// ===== VALIDATION =====
-
-	j := &SignedToken{header, payload}
+	j := &SignedToken{raw.Header, payload}
 	if !j.Validate() {
 		return nil, ErrInvalidToken(token)
 	}
 
-	method, err := jwa.New(j.Header.GetAlgorithm())
-	if err != nil {
-		return nil, err
-	}
-
-	var key interface{}
-	if getKey == nil {
-		return nil, ErrGetKey(token)
-	}
-	if key, err = getKey(header); err != nil {
-		return nil, err
-	}
-
-	if err := method.Verify(token[:lastDotIdx], segs[2], key); err != nil {
-		return nil, ErrInvalidSignature(token)
-	}
-
 	return j, nil
 }
 
 // EncodeAndSign creates a string representation of current token and appends a
 // signature.
 func (t *SignedToken) EncodeAndSign(key interface{}) (string, error) {
+	// Claims are encoded to their base64url representation
 	var buf bytes.Buffer
-
-	// HEADER
-	b64out := base64.NewEncoder(base64.RawURLEncoding, &buf)
-	jout := ffjson.NewEncoder(b64out)
-	if err := jout.Encode(t.Header); err != nil {
-		return "", err
-	}
-	b64out.Close()
-
-	buf.WriteString(".")
-
-	// PAYLOAD
 	if err := t.Payload.Encode(&buf); err != nil {
 		return "", err
 	}
-
-	// SIGNATURE
-	method, err := jwa.New(t.Header.GetAlgorithm())
-	if err != nil {
-		return "", err
-	}
-
-	sig, err := method.Sign(buf.String(), key)
+	payload, err := base64.RawURLEncoding.DecodeString(buf.String())
 	if err != nil {
 		return "", err
 	}
 
-	buf.WriteString(".")
-	buf.WriteString(sig)
-
-	return buf.String(), nil
+	raw := RawToken{t.Header, payload}
+	return raw.EncodeAndSign(key)
 }
 
 // Validate returns whether current token header and payload is valid.
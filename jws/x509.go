@@ -0,0 +1,192 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+var (
+	errNoRoots     = errors.New("no trusted root certificates defined")
+	errNoKeyUsages = errors.New("no acceptable key usages defined")
+)
+
+// An X509Resolver represents a key resolver which retrieves the key used to
+// sign a token from the certificate chain defined by its "x5c" header
+// parameter. The chain is validated against trusted root certificates before
+// the public key of its leaf certificate is used.
+type X509Resolver struct {
+	// Roots defines the set of trusted root certificates, which is required.
+	// The system pool is never used since any publicly trusted certificate
+	// would be able to sign tokens.
+	Roots *x509.CertPool
+
+	// Intermediates defines a set of intermediate certificates which are not
+	// required to be included in the chain of tokens.
+	Intermediates *x509.CertPool
+
+	// KeyUsages defines the acceptable extended key usages of leaf
+	// certificate, which is required. Any usage is only accepted when
+	// explicitly defined by x509.ExtKeyUsageAny.
+	KeyUsages []x509.ExtKeyUsage
+
+	// CurrentTime defines a function which returns the time to check the
+	// validity of certificates against. The current time is used when nil.
+	CurrentTime func() time.Time
+}
+
+// NewX509Resolver creates a new instance of X509Resolver which trusts
+// specified root certificates for specified extended key usages.
+func NewX509Resolver(
+	roots *x509.CertPool,
+	usages ...x509.ExtKeyUsage,
+) *X509Resolver {
+	return &X509Resolver{
+		Roots:     roots,
+		KeyUsages: usages,
+	}
+}
+
+// GetKey validates the certificate chain defined by specified header and
+// returns the public key of its leaf certificate. The thumbprints defined by
+// "x5t" and "x5t#S256" header parameters must match the leaf certificate.
+// This method can be used as a GetKeyFunc.
+func (r *X509Resolver) GetKey(header Header) (interface{}, error) {
+	if r.Roots == nil {
+		return nil, ErrInvalidChain{errNoRoots}
+	}
+	if len(r.KeyUsages) == 0 {
+		return nil, ErrInvalidChain{errNoKeyUsages}
+	}
+
+	regHeader, ok := header.(*RegHeader)
+	if !ok {
+		return nil, ErrGetKey(header.GetID())
+	}
+
+	chain, err := regHeader.GetX509Chain()
+	if err != nil {
+		return nil, ErrInvalidChain{err}
+	}
+	if len(chain) == 0 {
+		return nil, ErrGetKey(header.GetID())
+	}
+	leaf := chain[0]
+
+	if err := regHeader.checkX509Thumbprints(leaf); err != nil {
+		return nil, err
+	}
+
+	if leaf.KeyUsage != 0 &&
+		leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, ErrInvalidChain{x509.CertificateInvalidError{
+			Cert:   leaf,
+			Reason: x509.IncompatibleUsage,
+		}}
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         r.Roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     r.KeyUsages,
+	}
+	if r.Intermediates != nil {
+		opts.Intermediates = r.Intermediates.Clone()
+	}
+	for _, cert := range chain[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if r.CurrentTime != nil {
+		opts.CurrentTime = r.CurrentTime()
+	}
+
+	if _, err := leaf.Verify(opts); err != nil {
+		return nil, ErrInvalidChain{err}
+	}
+
+	return leaf.PublicKey, nil
+}
+
+// GetX509Chain parses the certificate chain defined by "x5c" header parameter.
+func (h *RegHeader) GetX509Chain() ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, 0, len(h.X509Chain))
+	for _, v := range h.X509Chain {
+		der, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, cert)
+	}
+
+	return chain, nil
+}
+
+// SetX509Chain sets "x5c" header parameter to specified certificate chain,
+// whose first certificate must contain the key used to sign the token, and
+// sets "x5t#S256" header parameter to match it.
+func (h *RegHeader) SetX509Chain(chain ...*x509.Certificate) {
+	h.X509Chain = make([]string, 0, len(chain))
+	for _, cert := range chain {
+		h.X509Chain = append(h.X509Chain,
+			base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+
+	h.X509SHA1 = ""
+	h.X509SHA256 = ""
+	if len(chain) > 0 {
+		sum := sha256.Sum256(chain[0].Raw)
+		h.X509SHA256 = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+}
+
+// checkX509Thumbprints checks whether the thumbprints defined by current
+// header match specified certificate.
+func (h *RegHeader) checkX509Thumbprints(cert *x509.Certificate) error {
+	if len(h.X509SHA1) > 0 {
+		sum := sha1.Sum(cert.Raw)
+		if !thumbprintEqual(h.X509SHA1, sum[:]) {
+			return ErrThumbprintMismatch("x5t")
+		}
+	}
+	if len(h.X509SHA256) > 0 {
+		sum := sha256.Sum256(cert.Raw)
+		if !thumbprintEqual(h.X509SHA256, sum[:]) {
+			return ErrThumbprintMismatch("x5t#S256")
+		}
+	}
+
+	return nil
+}
+
+// thumbprintEqual reports whether specified base64url encoded thumbprint
+// matches sum.
+func thumbprintEqual(thumbprint string, sum []byte) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(thumbprint)
+	return err == nil && bytes.Equal(decoded, sum)
+}
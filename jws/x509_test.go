@@ -0,0 +1,164 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jws_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/raiqub/jose/jwa"
+	_ "github.com/raiqub/jose/jwa/ecdsa"
+	"github.com/raiqub/jose/jws"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(
+	t *testing.T,
+	name string,
+	parent *testCert,
+	usage x509.KeyUsage,
+) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              usage,
+		BasicConstraintsValid: true,
+		IsCA:                  usage&x509.KeyUsageCertSign != 0,
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(
+		rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %v", err)
+	}
+
+	return &testCert{cert, key}
+}
+
+func TestX509Resolver(t *testing.T) {
+	root := newTestCert(t, "root", nil, x509.KeyUsageCertSign)
+	inter := newTestCert(t, "intermediate", root, x509.KeyUsageCertSign)
+	leaf := newTestCert(t, "leaf", inter, x509.KeyUsageDigitalSignature)
+	other := newTestCert(t, "other", nil, x509.KeyUsageCertSign)
+	encLeaf := newTestCert(t, "enc", inter, x509.KeyUsageKeyEncipherment)
+	otherLeaf := newTestCert(
+		t, "other leaf", other, x509.KeyUsageDigitalSignature)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	sign := func(h func(*jws.RegHeader), key interface{}) string {
+		token := jws.NewRawToken(jwa.ES256, []byte("payload"))
+		h(token.Header.(*jws.RegHeader))
+		str, err := token.EncodeAndSign(key)
+		if err != nil {
+			t.Fatalf("Error signing token: %v", err)
+		}
+		return str
+	}
+
+	usage := x509.ExtKeyUsageClientAuth
+	resolver := jws.NewX509Resolver(roots, usage)
+	expired := &jws.X509Resolver{
+		Roots:       roots,
+		KeyUsages:   []x509.ExtKeyUsage{usage},
+		CurrentTime: func() time.Time { return time.Now().Add(2 * time.Hour) },
+	}
+	untrusted := jws.NewX509Resolver(x509.NewCertPool(), usage)
+	untrusted.Roots.AddCert(other.cert)
+	noRoots := jws.NewX509Resolver(nil, usage)
+	noUsages := jws.NewX509Resolver(roots)
+
+	testCases := []struct {
+		name     string
+		resolver *jws.X509Resolver
+		header   func(*jws.RegHeader)
+		key      interface{}
+		valid    bool
+	}{
+		{"valid", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, leaf.key, true},
+		{"sha1", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+			h.X509SHA1 = "vnIyhnRIWY6F2GQu4ECrPBEhIak"
+		}, leaf.key, false},
+		{"thumbprint", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+			h.X509SHA256 = h.X509SHA256[1:] + "A"
+		}, leaf.key, false},
+		{"no chain", resolver, func(h *jws.RegHeader) {}, leaf.key, false},
+		{"incomplete", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert)
+		}, leaf.key, false},
+		{"expired", expired, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, leaf.key, false},
+		{"untrusted", untrusted, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, leaf.key, false},
+		{"other root", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(otherLeaf.cert, other.cert)
+		}, otherLeaf.key, false},
+		{"no roots", noRoots, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, leaf.key, false},
+		{"no usages", noUsages, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, leaf.key, false},
+		{"usage", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(encLeaf.cert, inter.cert)
+		}, encLeaf.key, false},
+		{"wrong key", resolver, func(h *jws.RegHeader) {
+			h.SetX509Chain(leaf.cert, inter.cert)
+		}, encLeaf.key, false},
+	}
+
+	for _, tc := range testCases {
+		token := sign(tc.header, tc.key)
+		_, err := jws.DecodeAndVerify(token, nil, tc.resolver.GetKey)
+		if (err == nil) != tc.valid {
+			t.Errorf("Unexpected result for %s chain: %v", tc.name, err)
+		}
+	}
+}